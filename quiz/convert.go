@@ -0,0 +1,52 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/pkg/errors"
+	"github.com/roberveral/gophercises/quiz/model"
+)
+
+// runConvert runs the convert subcommand with the given arguments, which translates a quiz
+// file between any of the supported formats.
+func runConvert(args []string) error {
+	flags := flag.NewFlagSet("convert", flag.ExitOnError)
+	from := flags.String("from", "", "Format of the input file, guessed from its extension if empty")
+	to := flags.String("to", "", "Format of the output file, guessed from its extension if empty")
+	flags.Usage = func() {
+		fmt.Fprintf(flags.Output(), "Usage:\n  %s convert [-from format] [-to format] input output\n\n"+
+			"Use '-' to read the input from the standard input or to write the output to the standard output.\n"+
+			"Supported formats: %v\n\nFlags:\n", os.Args[0], model.Formats())
+		flags.PrintDefaults()
+	}
+	flags.Parse(args)
+
+	if flags.NArg() != 2 {
+		flags.Usage()
+		os.Exit(2)
+	}
+	input, output := flags.Arg(0), flags.Arg(1)
+
+	quiz, err := loadQuiz(input, *from)
+	if err != nil {
+		return err
+	}
+
+	if output != "-" {
+		return model.Save(quiz, output, *to)
+	}
+
+	if *to == "" {
+		return errors.New("-to is required to write the quiz to the standard output")
+	}
+	format, err := model.FormatFor(output, *to)
+	if err != nil {
+		return err
+	}
+	if format.Save == nil {
+		return errors.Errorf("Unable to write quizzes in %s format", format.Name)
+	}
+	return format.Save(os.Stdout, quiz)
+}
@@ -0,0 +1,154 @@
+// Package expr evaluates arithmetic expressions like the ones asked in quizzes, e.g. "5+5"
+// or "(7-3)*2".
+package expr
+
+import (
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/pkg/errors"
+)
+
+// Evaluate evaluates an arithmetic expression with numbers, the operators +, -, * and /,
+// unary minus and parentheses. Multiplication and division take precedence over addition
+// and subtraction, and operators of the same precedence are applied from left to right.
+func Evaluate(expression string) (float64, error) {
+	p := &parser{input: expression}
+	value, err := p.expression()
+	if err != nil {
+		return 0, errors.Wrapf(err, "Invalid expression %q", expression)
+	}
+	if p.skipSpaces(); p.position < len(p.input) {
+		return 0, errors.Errorf("Invalid expression %q: unexpected %q at %d", expression, p.input[p.position], p.position)
+	}
+	return value, nil
+}
+
+// parser is a recursive descent parser which evaluates the expression as it parses it:
+//
+//	expression = term { ("+" | "-") term }
+//	term       = factor { ("*" | "/") factor }
+//	factor     = "-" factor | "(" expression ")" | number
+type parser struct {
+	input    string
+	position int
+}
+
+func (p *parser) expression() (float64, error) {
+	value, err := p.term()
+	if err != nil {
+		return 0, err
+	}
+
+	for {
+		switch p.next() {
+		case '+':
+			p.position++
+			operand, err := p.term()
+			if err != nil {
+				return 0, err
+			}
+			value += operand
+		case '-':
+			p.position++
+			operand, err := p.term()
+			if err != nil {
+				return 0, err
+			}
+			value -= operand
+		default:
+			return value, nil
+		}
+	}
+}
+
+func (p *parser) term() (float64, error) {
+	value, err := p.factor()
+	if err != nil {
+		return 0, err
+	}
+
+	for {
+		switch p.next() {
+		case '*':
+			p.position++
+			operand, err := p.factor()
+			if err != nil {
+				return 0, err
+			}
+			value *= operand
+		case '/':
+			p.position++
+			operand, err := p.factor()
+			if err != nil {
+				return 0, err
+			}
+			if operand == 0 {
+				return 0, errors.New("division by zero")
+			}
+			value /= operand
+		default:
+			return value, nil
+		}
+	}
+}
+
+func (p *parser) factor() (float64, error) {
+	switch p.next() {
+	case '-':
+		p.position++
+		value, err := p.factor()
+		return -value, err
+	case '(':
+		p.position++
+		value, err := p.expression()
+		if err != nil {
+			return 0, err
+		}
+		if p.next() != ')' {
+			return 0, errors.Errorf("missing ')' at %d", p.position)
+		}
+		p.position++
+		return value, nil
+	default:
+		return p.number()
+	}
+}
+
+func (p *parser) number() (float64, error) {
+	start := p.position
+	for p.position < len(p.input) && (isDigit(p.input[p.position]) || p.input[p.position] == '.') {
+		p.position++
+	}
+	if start == p.position {
+		if start == len(p.input) {
+			return 0, errors.New("unexpected end")
+		}
+		return 0, errors.Errorf("unexpected %q at %d", p.input[start], start)
+	}
+
+	value, err := strconv.ParseFloat(p.input[start:p.position], 64)
+	if err != nil {
+		return 0, errors.Errorf("invalid number %q", p.input[start:p.position])
+	}
+	return value, nil
+}
+
+// next returns the next character which isn't a space, without consuming it, or zero
+// at the end of the input.
+func (p *parser) next() byte {
+	p.skipSpaces()
+	if p.position < len(p.input) {
+		return p.input[p.position]
+	}
+	return 0
+}
+
+func (p *parser) skipSpaces() {
+	p.position += len(p.input[p.position:]) - len(strings.TrimLeftFunc(p.input[p.position:], unicode.IsSpace))
+}
+
+func isDigit(c byte) bool {
+	return '0' <= c && c <= '9'
+}
@@ -0,0 +1,37 @@
+package expr
+
+import "testing"
+
+func TestEvaluate(t *testing.T) {
+	expressions := map[string]float64{
+		"5+5":           10,
+		" 7 - 3 ":       4,
+		"2+3*4":         14,
+		"(2+3)*4":       20,
+		"10-4-3":        3,
+		"12/4/3":        1,
+		"-3*-2":         6,
+		"1.5*2":         3,
+		"7/2":           3.5,
+		"((1+2)*(3+4))": 21,
+	}
+
+	for expression, expected := range expressions {
+		value, err := Evaluate(expression)
+		if err != nil {
+			t.Errorf("Expected %q to be valid, but an error was returned: %+v", expression, err)
+			continue
+		}
+		if value != expected {
+			t.Errorf("Expected %q to be %v, but got %v", expression, expected, value)
+		}
+	}
+}
+
+func TestEvaluateInvalid(t *testing.T) {
+	for _, expression := range []string{"", "5+", "(5+5", "5+5)", "5/0", "five", "1..2", "5 5"} {
+		if value, err := Evaluate(expression); err == nil {
+			t.Errorf("Expected %q to be invalid, but got %v", expression, value)
+		}
+	}
+}
@@ -0,0 +1,256 @@
+// Package generator generates arithmetic problems procedurally from templates, so quizzes
+// don't have to be written by hand.
+package generator
+
+import (
+	"math/rand"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/roberveral/gophercises/quiz/expr"
+	"github.com/roberveral/gophercises/quiz/model"
+)
+
+// Operation is an arithmetic operation problems can be generated for.
+type Operation string
+
+const (
+	// Add generates additions, like "5+5"
+	Add Operation = "add"
+	// Sub generates subtractions, like "7-3"
+	Sub Operation = "sub"
+	// Mul generates multiplications, like "3*4"
+	Mul Operation = "mul"
+	// Div generates exact divisions, like "12/4"
+	Div Operation = "div"
+	// Mix generates expressions mixing additions, subtractions and multiplications, like "2+3*4"
+	Mix Operation = "mix"
+)
+
+// operations are the supported operations, in the order they are listed to the user.
+var operations = []Operation{Add, Sub, Mul, Div, Mix}
+
+// baseRatings are the Elo ratings of the problems of each operation with two single digit
+// operands, which grow with the number and the size of the operands.
+var baseRatings = map[Operation]float64{Add: 1200, Sub: 1300, Mul: 1400, Div: 1500, Mix: 1600}
+
+// symbols are the operators used to write each operation.
+var symbols = map[Operation]string{Add: "+", Sub: "-", Mul: "*", Div: "/"}
+
+// Difficulty is a level of difficulty of the generated problems, which sets the default
+// range of the operands and how many of them there are.
+type Difficulty struct {
+	// Name of the difficulty, as understood by ParseDifficulty
+	Name string
+	// Min and Max are the default range of the operands, both included
+	Min, Max int
+	// Operands is the number of operands in each problem
+	Operands int
+}
+
+var (
+	// Easy problems have two operands from 1 to 10
+	Easy = Difficulty{Name: "easy", Min: 1, Max: 10, Operands: 2}
+	// Medium problems have two operands from 1 to 100
+	Medium = Difficulty{Name: "medium", Min: 1, Max: 100, Operands: 2}
+	// Hard problems have three operands from 1 to 100
+	Hard = Difficulty{Name: "hard", Min: 1, Max: 100, Operands: 3}
+)
+
+var difficulties = []Difficulty{Easy, Medium, Hard}
+
+// ParseDifficulty parses the name of a Difficulty: "easy", "medium" or "hard".
+func ParseDifficulty(name string) (Difficulty, error) {
+	for _, difficulty := range difficulties {
+		if strings.EqualFold(strings.TrimSpace(name), difficulty.Name) {
+			return difficulty, nil
+		}
+	}
+	return Difficulty{}, errors.Errorf("Unknown difficulty %q, use easy, medium or hard", name)
+}
+
+// Template describes a kind of problem to generate.
+type Template struct {
+	// Operation of the problems
+	Operation Operation
+	// Min and Max are the range of the operands, both included. Divisions use it for the
+	// divisors and the quotients, so the answers are always whole numbers.
+	Min, Max int
+	// Operands is the number of operands in each problem, at least two
+	Operands int
+	// Difficulty the problems are tagged with
+	Difficulty string
+}
+
+// ParseTemplates parses a comma separated list of templates, each of them an operation with
+// an optional range of operands, e.g. "add,mul:1-12". Templates without range take it from
+// the given Difficulty, as well as the number of operands.
+func ParseTemplates(spec string, difficulty Difficulty) ([]Template, error) {
+	var templates []Template
+	for _, templateSpec := range strings.Split(spec, ",") {
+		template := Template{Min: difficulty.Min, Max: difficulty.Max, Operands: difficulty.Operands, Difficulty: difficulty.Name}
+
+		name, operandRange := templateSpec, ""
+		if i := strings.Index(templateSpec, ":"); i >= 0 {
+			name, operandRange = templateSpec[:i], templateSpec[i+1:]
+		}
+
+		template.Operation = Operation(strings.ToLower(strings.TrimSpace(name)))
+		if !isOperation(template.Operation) {
+			return nil, errors.Errorf("Unknown operation %q, use any of %v", name, operations)
+		}
+
+		if operandRange != "" {
+			var err error
+			if template.Min, template.Max, err = parseRange(operandRange); err != nil {
+				return nil, err
+			}
+		}
+
+		templates = append(templates, template)
+	}
+	return templates, nil
+}
+
+func isOperation(operation Operation) bool {
+	for _, supported := range operations {
+		if operation == supported {
+			return true
+		}
+	}
+	return false
+}
+
+// parseRange parses a range of operands like "1-12".
+func parseRange(operandRange string) (int, int, error) {
+	bounds := strings.SplitN(operandRange, "-", 2)
+	if len(bounds) != 2 {
+		return 0, 0, errors.Errorf("Invalid range %q, expected min-max", operandRange)
+	}
+
+	min, err := strconv.Atoi(strings.TrimSpace(bounds[0]))
+	if err != nil {
+		return 0, 0, errors.Errorf("Invalid range %q, expected min-max", operandRange)
+	}
+	max, err := strconv.Atoi(strings.TrimSpace(bounds[1]))
+	if err != nil {
+		return 0, 0, errors.Errorf("Invalid range %q, expected min-max", operandRange)
+	}
+	if min < 0 || min > max {
+		return 0, 0, errors.Errorf("Invalid range %q, expected 0 <= min <= max", operandRange)
+	}
+	return min, max, nil
+}
+
+// Generator generates random problems from a set of templates.
+type Generator struct {
+	templates []Template
+	rng       *rand.Rand
+}
+
+// New creates a Generator which picks at random among the given templates, using the given
+// source of randomness.
+func New(templates []Template, rng *rand.Rand) *Generator {
+	return &Generator{templates: templates, rng: rng}
+}
+
+// Generate generates the given number of problems. Their answers are computed by evaluating
+// the expression asked, and are compared numerically. Problems are rated by their operation
+// and the number and size of their operands, so they can be used in adaptive quizzes.
+func (g *Generator) Generate(count int) ([]model.Problem, error) {
+	if len(g.templates) == 0 {
+		return nil, errors.New("No templates to generate problems from")
+	}
+	if count < 0 {
+		return nil, errors.Errorf("Invalid number of problems %d", count)
+	}
+
+	problems := make([]model.Problem, count)
+	for i := range problems {
+		template := g.templates[g.rng.Intn(len(g.templates))]
+		expression, operands := g.expression(template)
+
+		answer, err := expr.Evaluate(expression)
+		if err != nil {
+			return nil, err
+		}
+
+		problems[i] = model.NewProblem(expression, []string{strconv.FormatFloat(answer, 'f', -1, 64)}, nil)
+		problems[i].Matcher = model.Numeric{}
+		problems[i].Category = string(template.Operation)
+		problems[i].Difficulty = rating(template.Operation, operands)
+		if template.Difficulty != "" {
+			problems[i].Tags = []string{template.Difficulty}
+		}
+	}
+	return problems, nil
+}
+
+// expression generates a random expression following the given template, returning it
+// along with its operands.
+func (g *Generator) expression(template Template) (string, []int) {
+	operands := make([]int, template.Operands)
+	if len(operands) < 2 {
+		operands = make([]int, 2)
+	}
+	for i := range operands {
+		operands[i] = g.operand(template)
+	}
+
+	switch template.Operation {
+	case Sub:
+		// The first operand is the largest, so two operand subtractions are never negative
+		for i := range operands[1:] {
+			if operands[i+1] > operands[0] {
+				operands[0], operands[i+1] = operands[i+1], operands[0]
+			}
+		}
+	case Div:
+		// The dividend is the product of a quotient and the divisors, so the division is exact
+		operands[0] = g.operand(template)
+		for i := range operands[1:] {
+			if operands[i+1] == 0 {
+				operands[i+1] = 1
+			}
+			operands[0] *= operands[i+1]
+		}
+	}
+
+	var builder strings.Builder
+	for i, operand := range operands {
+		if i > 0 {
+			builder.WriteString(g.symbol(template.Operation))
+		}
+		builder.WriteString(strconv.Itoa(operand))
+	}
+	return builder.String(), operands
+}
+
+// rating estimates the Elo rating of a problem of the given operation with the given operands:
+// each additional operand adds 150 points and each additional digit of the largest operand,
+// 100 points.
+func rating(operation Operation, operands []int) float64 {
+	largest := 0
+	for _, operand := range operands {
+		if operand > largest {
+			largest = operand
+		}
+	}
+	digits := len(strconv.Itoa(largest))
+	return baseRatings[operation] + 150*float64(len(operands)-2) + 100*float64(digits-1)
+}
+
+// operand returns a random operand in the range of the template.
+func (g *Generator) operand(template Template) int {
+	return template.Min + g.rng.Intn(template.Max-template.Min+1)
+}
+
+// symbol returns the operator for the operation, picking one at random for mixed ones.
+func (g *Generator) symbol(operation Operation) string {
+	if operation == Mix {
+		return []string{"+", "-", "*"}[g.rng.Intn(3)]
+	}
+	return symbols[operation]
+}
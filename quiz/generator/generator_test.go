@@ -0,0 +1,97 @@
+package generator
+
+import (
+	"math/rand"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/roberveral/gophercises/quiz/expr"
+)
+
+func TestParseTemplates(t *testing.T) {
+	templates, err := ParseTemplates("add, mul:1-12", Easy)
+	if err != nil {
+		t.Fatalf("Expected valid result, but an error was returned: %+v", err)
+	}
+
+	expected := []Template{
+		{Operation: Add, Min: 1, Max: 10, Operands: 2, Difficulty: "easy"},
+		{Operation: Mul, Min: 1, Max: 12, Operands: 2, Difficulty: "easy"},
+	}
+	if !reflect.DeepEqual(templates, expected) {
+		t.Errorf("Expected templates %+v, but got %+v", expected, templates)
+	}
+}
+
+func TestParseTemplatesInvalid(t *testing.T) {
+	for _, spec := range []string{"pow", "add:12", "add:a-b", "add:12-1", "add,"} {
+		if templates, err := ParseTemplates(spec, Easy); err == nil {
+			t.Errorf("Expected %q to be invalid, but got %+v", spec, templates)
+		}
+	}
+}
+
+func TestGenerate(t *testing.T) {
+	templates, _ := ParseTemplates("add,sub,mul:1-12,div:2-9,mix", Hard)
+	problems, err := New(templates, rand.New(rand.NewSource(1))).Generate(200)
+	if err != nil {
+		t.Fatalf("Expected valid result, but an error was returned: %+v", err)
+	}
+	if len(problems) != 200 {
+		t.Fatalf("Expected 200 problems, but got %d", len(problems))
+	}
+
+	for _, problem := range problems {
+		operands := strings.FieldsFunc(problem.Question, func(r rune) bool { return strings.ContainsRune("+-*/", r) })
+		if len(operands) != 3 {
+			t.Errorf("Expected 3 operands in %q, but got %v", problem.Question, operands)
+		}
+
+		value, err := expr.Evaluate(problem.Question)
+		if err != nil {
+			t.Errorf("Expected %q to be valid, but an error was returned: %+v", problem.Question, err)
+			continue
+		}
+		if !problem.CheckAnswer(strconv.FormatFloat(value, 'f', -1, 64)) {
+			t.Errorf("Expected %v to answer %q, but answers are %v", value, problem.Question, problem.Answers)
+		}
+		if problem.Category == "div" && value != float64(int(value)) {
+			t.Errorf("Expected exact division in %q, but got %v", problem.Question, value)
+		}
+		if !reflect.DeepEqual(problem.Tags, []string{"hard"}) {
+			t.Errorf("Expected problem tagged as hard, but got %v", problem.Tags)
+		}
+	}
+}
+
+func TestGenerateRejectsNegativeCount(t *testing.T) {
+	templates, _ := ParseTemplates("add", Easy)
+	if _, err := New(templates, rand.New(rand.NewSource(1))).Generate(-1); err == nil {
+		t.Error("Expected an error for a negative number of problems, but got none")
+	}
+}
+
+func TestGenerateIsReproducible(t *testing.T) {
+	templates, _ := ParseTemplates("add,mul", Medium)
+	first, _ := New(templates, rand.New(rand.NewSource(42))).Generate(10)
+	second, _ := New(templates, rand.New(rand.NewSource(42))).Generate(10)
+
+	if !reflect.DeepEqual(first, second) {
+		t.Errorf("Expected the same problems with the same seed, but got %v and %v", first, second)
+	}
+}
+
+func TestRatingGrowsWithOperands(t *testing.T) {
+	easy := rating(Add, []int{3, 4})
+	bigger := rating(Add, []int{30, 4})
+	longer := rating(Add, []int{3, 4, 5})
+
+	if easy != 1200 || bigger != 1300 || longer != 1350 {
+		t.Errorf("Expected ratings 1200, 1300 and 1350, but got %v, %v and %v", easy, bigger, longer)
+	}
+	if rating(Mul, []int{3, 4}) <= easy {
+		t.Errorf("Expected multiplications to be rated higher than additions")
+	}
+}
@@ -1,3 +1,9 @@
 module github.com/roberveral/gophercises/quiz
 
-require github.com/pkg/errors v0.8.0
+go 1.16
+
+require (
+	github.com/BurntSushi/toml v0.3.1
+	github.com/pkg/errors v0.8.0
+	gopkg.in/yaml.v2 v2.2.2
+)
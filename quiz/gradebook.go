@@ -0,0 +1,45 @@
+package main
+
+import (
+	"flag"
+	"os"
+
+	"github.com/pkg/errors"
+	"github.com/roberveral/gophercises/quiz/results"
+)
+
+// runGradebook runs the gradebook subcommand with the given arguments, which exports the grades
+// of the runs recorded in a results store as CSV for the gradebook of an LMS.
+func runGradebook(args []string) error {
+	flags := flag.NewFlagSet("gradebook", flag.ExitOnError)
+	resultsPath := flags.String("results", "results.jsonl", "Path to the results store")
+	quiz := flags.String("quiz", "", "Only export the grades of the given quiz")
+	gradingName := flags.String("grading", "highest", "Grade of the users who took a quiz several times: highest, average, first or last run")
+	outputPath := flags.String("output", "-", "Path to write the gradebook to, or '-' to write it to the standard output")
+	flags.Parse(args)
+
+	grading, err := results.ParseGrading(*gradingName)
+	if err != nil {
+		return err
+	}
+	runs, err := results.NewStore(*resultsPath).Runs()
+	if err != nil {
+		return err
+	}
+	gradebook := results.NewGradebook(results.Filter(runs, "", *quiz), grading)
+
+	if *outputPath == "-" {
+		return gradebook.WriteCSV(os.Stdout)
+	}
+
+	file, err := os.Create(*outputPath)
+	if err != nil {
+		return errors.Wrap(err, "Unable to create gradebook file")
+	}
+	defer file.Close()
+
+	if err := gradebook.WriteCSV(file); err != nil {
+		return err
+	}
+	return errors.Wrap(file.Close(), "Unable to write gradebook file")
+}
@@ -1,31 +1,462 @@
 package main
 
 import (
+	"context"
 	"flag"
 	"fmt"
+	"math/rand"
+	"net"
+	"net/http"
 	"os"
+	"os/user"
+	"path/filepath"
+	"strings"
+	"sync"
 	"time"
 
+	"github.com/pkg/errors"
+	"github.com/roberveral/gophercises/quiz/generator"
+	"github.com/roberveral/gophercises/quiz/practice"
+	"github.com/roberveral/gophercises/quiz/report"
+	"github.com/roberveral/gophercises/quiz/results"
 	"github.com/roberveral/gophercises/quiz/runner"
+	"github.com/roberveral/gophercises/quiz/server"
 
 	"github.com/roberveral/gophercises/quiz/model"
 )
 
 func main() {
-	csvPath := flag.String("csv", "problems.csv", "Path to the CSV file with the problems in the form 'question,answer'")
-	timeout := flag.Duration("timeout", 30*time.Second, "Timeout for the user to complete the quiz")
+	if len(os.Args) > 1 {
+		subcommands := map[string]func([]string) error{"stats": runStats, "validate": runValidate, "convert": runConvert, "gradebook": runGradebook}
+		if subcommand, ok := subcommands[os.Args[1]]; ok {
+			exitOnError(subcommand(os.Args[2:]))
+			return
+		}
+	}
+
+	csvPath := flag.String("csv", "problems.csv", "Path to the CSV file with the problems in the form 'question,answer[,choices[,matcher[,timeout[,points[,explanation[,category[,difficulty[,tags[,markdown[,code[,language[,image]]]]]]]]]]]]]', or '-' to read it from the standard input")
+	filePath := flag.String("file", "", "Path to the quiz file, in any of the supported formats (overrides -csv), or '-' to read it from the standard input")
+	format := flag.String("format", "", "Format of the quiz file: csv, json, yaml, toml, gift or qti. Guessed from the file extension by default")
+	generate := flag.String("generate", "", "Generate arithmetic problems instead of loading them, from a comma separated list of operations (add, sub, mul, div or mix) with optional operand ranges, e.g. 'add,mul:1-12'")
+	count := flag.Int("count", 10, "Number of problems to generate with -generate")
+	difficulty := flag.String("difficulty", "easy", "Difficulty of the generated problems: easy, medium or hard")
+	tags := flag.String("tags", "", "Comma separated tags or categories of the problems to take, e.g. 'algebra,geometry'. All the problems are taken by default")
+	sampleSize := flag.Int("sample", 0, "Number of problems to pick at random among the ones with the given tags, all if zero")
+	stratify := flag.Bool("stratify", false, "Spread the sample evenly among the given tags, or among all the tags if none is given (requires -sample)")
+	seed := flag.Int64("seed", 0, "Seed of the random choices, like shuffling, sampling or generating problems, printed in the results so the same quiz can be taken again. Random if zero")
+	match := flag.String("match", "exact", "Default answer matcher: exact, fold, numeric[:tolerance], regex or fuzzy[:distance]")
+	timeout := flag.Duration("timeout", 30*time.Second, "Timeout for the user to complete the quiz, unlimited if zero")
+	questionTimeout := flag.Duration("question-timeout", 0, "Timeout for the user to answer each problem, unlimited if zero")
+	penalty := flag.Float64("penalty", 0, "Fraction of the points of a problem deducted for a wrong answer, e.g. 0.25")
+	partialCredit := flag.Bool("partial-credit", false, "Award partial credit to multiple answer problems")
+	passMark := flag.Float64("pass-mark", 0, "Percentage of the maximum points needed to pass the quiz, none if zero")
+	adaptive := flag.Bool("adaptive", false, "Pick each problem by its difficulty, harder or easier depending on the previous answers, and estimate the ability of the user")
+	feedback := flag.Bool("feedback", false, "Show whether each answer is correct right after answering, with the expected answer and its explanation")
 	shuffle := flag.Bool("shuffle", false, "Whether to shuffle the quiz problems or not")
+	reportPath := flag.String("report", "", "Path to write the detailed report of the quiz to, either a .json or a .csv file. Not available with -multi or -listen")
+	plain := flag.Bool("plain", false, "Show the quiz as plain text in the console, without progress bar, countdown nor colors")
+	serve := flag.String("serve", "", "Address to serve the quiz in, e.g. ':8080', to take it from a browser instead of the console")
+	multi := flag.Bool("multi", false, "Serve the quiz to several participants, each with its own session, ranked in a leaderboard (requires -serve)")
+	listen := flag.String("listen", "", "Address to listen for TCP connections in, e.g. ':4000', so each connection takes its own quiz with netcat or telnet")
+	teams := flag.Int("teams", 0, "Wait for the given number of teams to connect with -listen and run a head-to-head match, in which only the first correct answer to each question scores")
+	practicePath := flag.String("practice", "", "Path to the practice history file, e.g. 'history.json'. Practices the problems due for review first and records the answers to schedule the next reviews")
+	sessionPath := flag.String("session", "", "Path to save the progress of the quiz to after each answer, so it can be resumed with -resume if it is interrupted")
+	resumePath := flag.String("resume", "", "Path to the session file of an interrupted quiz to resume, saved with -session")
+	resultsPath := flag.String("results", "", "Path to the results store where each completed run is recorded, e.g. results.jsonl, see 'quiz stats'. Runs aren't recorded by default")
+	userName := flag.String("user", currentUser(), "Name of the user taking the quiz, as recorded in the results store")
+	minAnswerTime := flag.Duration("min-answer-time", 0, "Least time in which a problem can be honestly answered. Faster answers are flagged as suspicious in the results, none if zero")
+	auditPath := flag.String("audit", "", "Path to the audit log where each answer is recorded with its time, e.g. 'audit.jsonl'. Answers aren't audited if empty")
+	maxAttempts := flag.Int("max-attempts", 0, "Number of times each user can take the quiz within -attempts-window, according to the results store. Unlimited if zero")
+	attemptsWindow := flag.Duration("attempts-window", 24*time.Hour, "Period of time in which the attempts are limited by -max-attempts, forever if zero")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "Usage:\n  %s [flags]\n  %s stats [-results path] [-user name] [-quiz name] [-top n]\n"+
+			"  %s validate [-format name] file...\n  %s convert [-from format] [-to format] input output\n"+
+			"  %s gradebook [-results path] [-quiz name] [-grading method] [-output path]\n\nFlags:\n",
+			os.Args[0], os.Args[0], os.Args[0], os.Args[0], os.Args[0])
+		flag.PrintDefaults()
+	}
 	flag.Parse()
 
-	quiz, err := model.LoadFromCSV(*csvPath)
+	if (*practicePath != "" || *resumePath != "") && (*multi || *listen != "") {
+		exitOnError(errors.New("-practice and -resume can't be combined with -multi or -listen"))
+	}
+	if *reportPath != "" && (*multi || *listen != "") {
+		exitOnError(errors.New("-report can't be combined with -multi or -listen, use -results instead"))
+	}
+	if *reportPath != "" {
+		exitOnError(report.CheckPath(*reportPath))
+	}
+	if *stratify && *sampleSize <= 0 {
+		exitOnError(errors.New("-stratify requires -sample"))
+	}
+	if *teams > 0 && *listen == "" {
+		exitOnError(errors.New("-teams requires -listen"))
+	}
+	if *generate != "" && *count < 0 {
+		exitOnError(errors.Errorf("Invalid number of problems to generate %d", *count))
+	}
+	if *maxAttempts > 0 && *resultsPath == "" {
+		exitOnError(errors.New("-max-attempts requires -results to count the attempts"))
+	}
+
+	path, formatName := *csvPath, "csv"
+	if *filePath != "" {
+		path, formatName = *filePath, *format
+	}
+	if *seed == 0 {
+		*seed = time.Now().UnixNano()
+	}
+	rng := rand.New(rand.NewSource(*seed))
+
+	var quiz *model.Quiz
+	var session *model.Session
+	var err error
+	switch {
+	case *resumePath != "":
+		if session, err = model.LoadSession(*resumePath); err == nil {
+			quiz = session.Quiz
+		}
+	case *generate != "":
+		quiz, err = generateQuiz(*generate, *difficulty, *count, rng)
+	default:
+		// Images are found relative to the quiz file
+		if quiz, err = loadQuiz(path, formatName); err == nil && path != "-" {
+			quiz.ResolveImages(filepath.Dir(path))
+		}
+	}
+	exitOnError(err)
+
+	if session == nil && (*tags != "" || *sampleSize > 0) {
+		selection := model.Selection{Sample: *sampleSize, Stratify: *stratify}
+		if *tags != "" {
+			selection.Tags = strings.Split(*tags, ",")
+		}
+		quiz, err = quiz.Select(selection, rng)
+		exitOnError(err)
+	}
+
+	// Quizzes without title are named after their file in the results, and the name is kept
+	// in the sessions so resumed quizzes are recorded the same way
+	if quiz.Title == "" {
+		quiz.Title = filepath.Base(path)
+		if *generate != "" {
+			quiz.Title = *generate
+		}
+	}
+	// The store is shared by all the runs, so the ones taken at once are recorded in turns
+	var store *results.Store
+	if *resultsPath != "" {
+		store = results.NewStore(*resultsPath)
+	}
+	record := func(name string, quizReport *report.Report) {
+		run := results.Run{Time: time.Now(), User: name, Quiz: quiz.Title, Report: quizReport}
+		if tooFast := quizReport.TooFast(); tooFast > 0 {
+			fmt.Printf("%s answered %d questions suspiciously fast\n", name, tooFast)
+		}
+		if store != nil {
+			if err := store.Record(run); err != nil {
+				fmt.Printf("Unable to record the results: %v\n", err)
+			}
+		}
+	}
+	// Answers are audited as soon as they are scored, so the ones of runs which are never
+	// completed are audited too
+	var auditLog *results.AuditLog
+	if *auditPath != "" {
+		auditLog = results.NewAuditLog(*auditPath)
+	}
+	audit := func(name string, question report.Question) {
+		if auditLog != nil {
+			if err := auditLog.Record(name, quiz.Title, question); err != nil {
+				fmt.Printf("Unable to audit the answer: %v\n", err)
+			}
+		}
+	}
+	checkAttempts := func(name string, pending int) error {
+		if *maxAttempts <= 0 {
+			return nil
+		}
+		runs, err := store.Runs()
+		if err != nil {
+			return err
+		}
+		return results.RetakeLimit{Max: *maxAttempts, Window: *attemptsWindow}.Check(runs, pending, name, quiz.Title, time.Now())
+	}
+
+	// Flags only override the settings of the quiz file when given explicitly. Resumed
+	// sessions keep the settings they were started with.
+	if session == nil {
+		flag.Visit(func(f *flag.Flag) {
+			switch f.Name {
+			case "match":
+				quiz.Matcher, err = model.ParseMatcher(*match)
+				exitOnError(err)
+			case "question-timeout":
+				quiz.QuestionTimeout = *questionTimeout
+			case "penalty":
+				quiz.Scoring.Penalty = *penalty
+			case "partial-credit":
+				quiz.Scoring.PartialCredit = *partialCredit
+			case "pass-mark":
+				quiz.Scoring.PassMark = *passMark
+			case "adaptive":
+				quiz.Adaptive = *adaptive
+			case "feedback":
+				quiz.Feedback = *feedback
+			case "min-answer-time":
+				quiz.MinAnswerTime = *minAnswerTime
+			}
+		})
+		exitOnError(quiz.Scoring.Validate())
+	}
+
+	var history *practice.History
+	if *practicePath != "" {
+		history, err = practice.LoadHistory(*practicePath)
+		exitOnError(err)
+		// Practice replaces the shuffle with the order given by the history
+		if session == nil {
+			history.Order(quiz, time.Now())
+		}
+	} else if *shuffle && session == nil && !*multi && (*listen == "" || *teams > 0) {
+		// Quizzes served to several participants are shuffled from the file order for each of
+		// them instead, so the seed reported to each participant gives their order back
+		quiz.Shuffle(rng)
+	}
+
+	if session == nil && (*shuffle || *sampleSize > 0 || *generate != "") {
+		quiz.Seed = *seed
+	}
+
+	if *multi {
+		if *serve == "" {
+			exitOnError(errors.New("-multi requires -serve"))
+		}
+		fmt.Printf("Serving the quiz for several participants in %s\n", *serve)
+		quizServer := server.New(quiz, *timeout, *shuffle)
+		quizServer.OnJoin = checkAttempts
+		quizServer.OnFinish = record
+		quizServer.OnAnswer = audit
+		exitOnError(http.ListenAndServe(*serve, quizServer))
+		return
+	}
+
+	if *listen != "" {
+		listener, err := net.Listen("tcp", *listen)
+		exitOnError(err)
+
+		if *teams > 0 {
+			// The answers of a match are scored when it is over, so they are audited along with the reports
+			exitOnError(runMatch(listener, *teams, quiz, *timeout, func(name string, quizReport *report.Report) {
+				for _, question := range quizReport.Questions {
+					audit(name, question)
+				}
+				record(name, quizReport)
+			}))
+			return
+		}
+
+		// Connections are served concurrently, so the seeds of their shuffles are taken in turns
+		var seedMutex sync.Mutex
+		// The connections in progress of each host count as attempts until they are recorded
+		var attemptsMutex sync.Mutex
+		pending := make(map[string]int)
+		fmt.Printf("Listening for quiz takers in %s\n", listener.Addr())
+		exitOnError(runner.ServeTCP(listener, func(tcpRunner *runner.TCPRunner) {
+			// Connections are recorded by host, so the attempts from the same host are counted together
+			host, _, _ := net.SplitHostPort(tcpRunner.RemoteAddr().String())
+			attemptsMutex.Lock()
+			err := checkAttempts(host, pending[host])
+			if err == nil {
+				pending[host]++
+			}
+			attemptsMutex.Unlock()
+			if err != nil {
+				fmt.Printf("%s was rejected: %v\n", tcpRunner.RemoteAddr(), err)
+				tcpRunner.Reject(err.Error())
+				return
+			}
+
+			// Each connection takes its own copy of the quiz, with its own order and timer
+			connectionQuiz := quiz.Copy()
+			if *shuffle {
+				seedMutex.Lock()
+				connectionSeed := rng.Int63()
+				seedMutex.Unlock()
+				connectionQuiz = quiz.ShuffledCopy(connectionSeed)
+			}
+			connectionQuiz.OnAnswer = func(question report.Question) { audit(host, question) }
+			var timer *time.Timer
+			if *timeout > 0 {
+				timer = time.NewTimer(*timeout)
+			}
+			quizReport := connectionQuiz.Execute(context.Background(), tcpRunner, timer)
+			fmt.Printf("%s scored %v out of %v\n", tcpRunner.RemoteAddr(), quizReport.Correct, quizReport.Total)
+			record(host, quizReport)
+
+			attemptsMutex.Lock()
+			pending[host]--
+			attemptsMutex.Unlock()
+		}))
+		return
+	}
+
+	// When the quiz comes from the standard input the answers are read from the terminal
+	answers := os.Stdin
+	if path == "-" && session == nil {
+		answers, err = os.Open("/dev/tty")
+		exitOnError(errors.Wrap(err, "Unable to read the answers from the terminal"))
+	}
+
+	// The terminal UI falls back to plain text when the output isn't a terminal
+	quizRunner := runner.NewTUIRunner(answers, os.Stdout)
+	if *plain {
+		quizRunner = runner.NewIoRunner(answers, os.Stdout)
+	}
+	// Resumed sessions aren't recorded until they are finished, so they are checked as well, and
+	// before serving the quiz so the user isn't rejected after opening it
+	exitOnError(checkAttempts(*userName, 0))
+	var webRunner *runner.WebRunner
+	if *serve != "" {
+		webRunner = runner.NewWebRunner()
+		go func() { exitOnError(http.ListenAndServe(*serve, webRunner)) }()
+
+		// The timer doesn't start until the user opens the quiz
+		fmt.Printf("Serving the quiz in %s, open it in your browser to start\n", *serve)
+		<-webRunner.Connected()
+		quizRunner = webRunner
+	}
+
+	// The progress is saved after each answer to the session file, the resumed one by default
+	checkpointPath := *sessionPath
+	if checkpointPath == "" {
+		checkpointPath = *resumePath
+	}
+	var checkpoint func(*model.Session)
+	if checkpointPath != "" {
+		checkpoint = func(s *model.Session) {
+			if err := s.Save(checkpointPath); err != nil {
+				fmt.Printf("\nUnable to save the progress of the quiz: %v\n", err)
+			}
+		}
+	}
+
+	if session == nil {
+		session = model.NewSession(quiz, *timeout)
+	}
+	session.Quiz.OnAnswer = func(question report.Question) { audit(*userName, question) }
+	if _, ok := quizRunner.(runner.Pauser); ok {
+		fmt.Printf("Answer %s to any problem to pause the quiz\n", runner.PauseCommand)
+	}
+	quizReport := session.Run(context.Background(), quizRunner, checkpoint)
+	record(*userName, quizReport)
+
+	if webRunner != nil {
+		// Give the browser the chance to show the results before exiting
+		select {
+		case <-webRunner.Finished():
+		case <-time.After(30 * time.Second):
+		}
+	}
+
+	if history != nil {
+		history.Record(quizReport, time.Now())
+		exitOnError(history.Save(*practicePath))
+	}
+
+	if *reportPath != "" {
+		exitOnError(quizReport.WriteFile(*reportPath))
+	}
+}
+
+// runMatch waits for the given number of teams to connect to the listener and runs a head-to-head
+// match between them, which has to be completed in the given time, or without time limit if zero.
+// The report of each team is recorded with the given function.
+func runMatch(listener net.Listener, teams int, quiz *model.Quiz, timeout time.Duration, record func(string, *report.Report)) error {
+	contestants := make(chan model.Contestant)
+	over := make(chan struct{})
+	go runner.ServeTCP(listener, func(tcpRunner *runner.TCPRunner) {
+		// The connection is kept open until the match is over, and the late ones are just closed
+		select {
+		case contestants <- model.Contestant{Name: tcpRunner.RemoteAddr().String(), Runner: tcpRunner}:
+			<-over
+		case <-over:
+		}
+	})
+
+	fmt.Printf("Waiting for %d teams to connect to %s\n", teams, listener.Addr())
+	match := make([]model.Contestant, teams)
+	for i := range match {
+		match[i] = <-contestants
+		fmt.Printf("%s joined the match (%d of %d)\n", match[i].Name, i+1, teams)
+	}
+	defer close(over)
+	defer listener.Close()
+
+	ctx, cancel := context.Background(), func() {}
+	if timeout > 0 {
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+	}
+	defer cancel()
+
+	fmt.Println("The match has started")
+	standings := quiz.Compete(ctx, match)
+
+	fmt.Println("Final standings:")
+	for _, standing := range standings {
+		fmt.Printf("  %d. %s: %v points (%d correct)\n", standing.Rank, standing.Name, standing.Points, standing.Correct)
+		record(standing.Name, standing.Report)
+	}
+	return nil
+}
+
+// loadQuiz loads the quiz in the given path, or from the standard input if the path is "-".
+func loadQuiz(path, formatName string) (*model.Quiz, error) {
+	if path != "-" {
+		return model.Load(path, formatName)
+	}
+
+	if formatName == "" {
+		return nil, errors.New("-format is required to read the quiz from the standard input")
+	}
+	format, err := model.FormatFor(path, formatName)
 	if err != nil {
-		fmt.Printf("An error occured: %v\n", err)
-		os.Exit(1)
+		return nil, err
 	}
+	return format.Load(os.Stdin)
+}
 
-	if *shuffle {
-		quiz.Shuffle()
+// generateQuiz generates a quiz with the given number of problems from the given templates
+// (see generator.ParseTemplates) and difficulty, using the given source of randomness.
+func generateQuiz(spec, difficultyName string, count int, rng *rand.Rand) (*model.Quiz, error) {
+	difficulty, err := generator.ParseDifficulty(difficultyName)
+	if err != nil {
+		return nil, err
+	}
+	templates, err := generator.ParseTemplates(spec, difficulty)
+	if err != nil {
+		return nil, err
 	}
 
-	quiz.Execute(runner.NewIoRunner(os.Stdin, os.Stdout), time.NewTimer(*timeout))
+	problems, err := generator.New(templates, rng).Generate(count)
+	if err != nil {
+		return nil, err
+	}
+	return &model.Quiz{Problems: problems}, nil
+}
+
+// currentUser returns the name of the user running the quiz, as given by the system.
+func currentUser() string {
+	if current, err := user.Current(); err == nil {
+		return current.Username
+	}
+	return os.Getenv("USER")
+}
+
+// exitOnError finishes the program showing the error, if any.
+func exitOnError(err error) {
+	if err != nil {
+		fmt.Printf("An error occured: %v\n", err)
+		os.Exit(1)
+	}
 }
@@ -0,0 +1,54 @@
+package model
+
+import "math"
+
+const (
+	// DefaultRating is the Elo rating of the problems without difficulty, which is also the
+	// ability the user starts with in adaptive quizzes.
+	DefaultRating = 1500
+	// abilityK is the maximum change of the ability after each answer. It is higher than the
+	// usual Elo K-factor, so the estimate converges in a short quiz.
+	abilityK = 64
+)
+
+// Rating returns the Elo rating of the difficulty of the problem, DefaultRating if unrated.
+func (q *Problem) Rating() float64 {
+	if q.Difficulty == 0 {
+		return DefaultRating
+	}
+	return q.Difficulty
+}
+
+// expectedScore returns the probability that a user with the given ability answers correctly
+// a problem with the given rating, as defined by Elo.
+func expectedScore(ability, rating float64) float64 {
+	return 1 / (1 + math.Pow(10, (rating-ability)/400))
+}
+
+// updateAbility returns the ability of a user after scoring the given fraction, from 0 to 1,
+// of the points of a problem with the given rating.
+func updateAbility(ability, rating, score float64) float64 {
+	return ability + abilityK*(score-expectedScore(ability, rating))
+}
+
+// pickNext moves to the front of the given problems the one closest in rating to the given
+// ability, so the user gets harder problems while answering correctly and easier ones after
+// failing. On a tie the earliest problem is kept, so the order of the quiz is respected.
+func pickNext(problems []Problem, ability float64) {
+	best := 0
+	for i := range problems {
+		if math.Abs(problems[i].Rating()-ability) < math.Abs(problems[best].Rating()-ability) {
+			best = i
+		}
+	}
+	// Rotating instead of swapping keeps the order of the rest of the problems
+	picked := problems[best]
+	copy(problems[1:best+1], problems[:best])
+	problems[0] = picked
+}
+
+// scoreFraction returns the fraction of the maximum points of a question scored with an answer,
+// between 0 and 1.
+func scoreFraction(points, maxPoints float64) float64 {
+	return math.Max(0, math.Min(1, points/maxPoints))
+}
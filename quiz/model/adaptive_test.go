@@ -0,0 +1,98 @@
+package model
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/roberveral/gophercises/quiz/report"
+	"github.com/roberveral/gophercises/quiz/runner"
+)
+
+// knowingRunner answers correctly only the questions it knows, recording the order
+// in which they were asked.
+type knowingRunner struct {
+	known map[string]string
+	asked []string
+}
+
+func (r *knowingRunner) Ask(ctx context.Context, number int, question runner.Question) string {
+	r.asked = append(r.asked, question.Text)
+	return r.known[question.Text]
+}
+
+func (r *knowingRunner) ShowResults(quizReport *report.Report)   {}
+func (r *knowingRunner) NotifyTimeout(quizReport *report.Report) {}
+func (r *knowingRunner) NotifyQuestionTimeout(number int)        {}
+
+func ratedProblem(question, answer string, difficulty float64) Problem {
+	problem := NewProblem(question, []string{answer}, nil)
+	problem.Difficulty = difficulty
+	return problem
+}
+
+func adaptiveQuiz() *Quiz {
+	return &Quiz{Adaptive: true, Problems: []Problem{
+		ratedProblem("easy", "1", 1200),
+		ratedProblem("easier", "2", 1000),
+		ratedProblem("average", "3", 1500),
+		ratedProblem("hard", "4", 1800),
+		ratedProblem("harder", "5", 2000),
+	}}
+}
+
+func TestAdaptiveExecuteAsksHarderProblemsAfterCorrectAnswers(t *testing.T) {
+	quizRunner := &knowingRunner{known: map[string]string{"average": "3", "hard": "4", "harder": "5"}}
+
+	quizReport := adaptiveQuiz().Execute(context.Background(), quizRunner, time.NewTimer(time.Minute))
+
+	if quizRunner.asked[0] != "average" || quizRunner.asked[1] != "hard" {
+		t.Errorf("Expected average and hard problems first, but got %v", quizRunner.asked)
+	}
+	if quizReport.Correct != 3 || len(quizReport.Questions) != 5 {
+		t.Errorf("Expected 3 correct answers out of 5, but got %+v", quizReport)
+	}
+	if quizReport.Ability <= DefaultRating {
+		t.Errorf("Expected ability above %v, but got %v", DefaultRating, quizReport.Ability)
+	}
+}
+
+func TestAdaptiveExecuteAsksEasierProblemsAfterWrongAnswers(t *testing.T) {
+	quizRunner := &knowingRunner{known: map[string]string{"easier": "2"}}
+
+	quizReport := adaptiveQuiz().Execute(context.Background(), quizRunner, time.NewTimer(time.Minute))
+
+	if quizRunner.asked[0] != "average" || quizRunner.asked[1] != "easy" {
+		t.Errorf("Expected average and easy problems first, but got %v", quizRunner.asked)
+	}
+	if quizReport.Ability >= DefaultRating {
+		t.Errorf("Expected ability below %v, but got %v", DefaultRating, quizReport.Ability)
+	}
+}
+
+func TestExecuteKeepsOrderWhenNotAdaptive(t *testing.T) {
+	quiz := adaptiveQuiz()
+	quiz.Adaptive = false
+	quizRunner := &knowingRunner{}
+
+	quizReport := quiz.Execute(context.Background(), quizRunner, time.NewTimer(time.Minute))
+
+	if quizRunner.asked[0] != "easy" || quizReport.Ability != 0 {
+		t.Errorf("Expected problems in order without ability, but got %v and %v", quizRunner.asked, quizReport.Ability)
+	}
+	if quiz.Problems[0].Question != "easy" {
+		t.Errorf("Expected the problems of the quiz not to be reordered, but got %v", quiz.Problems[0].Question)
+	}
+}
+
+func TestUpdateAbility(t *testing.T) {
+	if ability := updateAbility(1500, 1500, 1); ability != 1532 {
+		t.Errorf("Expected ability of 1532 after an even win, but got %v", ability)
+	}
+	if ability := updateAbility(1500, 1500, 0); ability != 1468 {
+		t.Errorf("Expected ability of 1468 after an even loss, but got %v", ability)
+	}
+	if updateAbility(1500, 1100, 1) >= updateAbility(1500, 1900, 1) {
+		t.Errorf("Expected harder problems to raise the ability more")
+	}
+}
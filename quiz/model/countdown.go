@@ -0,0 +1,64 @@
+package model
+
+import "time"
+
+// countdown is a timer which can be paused, keeping the time remaining until it goes off.
+// A countdown without time never goes off.
+type countdown struct {
+	timer     *time.Timer
+	remaining time.Duration
+	started   time.Time
+}
+
+// startCountdown starts a countdown which goes off after the given time, or never if zero.
+func startCountdown(remaining time.Duration) *countdown {
+	c := &countdown{remaining: remaining}
+	c.resume()
+	return c
+}
+
+// expired returns a channel which receives when the countdown goes off. It is nil, so it
+// blocks forever, for countdowns without time and while paused.
+func (c *countdown) expired() <-chan time.Time {
+	if c.timer == nil {
+		return nil
+	}
+	return c.timer.C
+}
+
+// pause stops the countdown, keeping the remaining time.
+func (c *countdown) pause() {
+	if c.timer == nil {
+		return
+	}
+	c.remaining = c.left()
+	c.timer.Stop()
+	c.timer = nil
+}
+
+// resume restarts a paused countdown with the time it had remaining.
+func (c *countdown) resume() {
+	if c.remaining > 0 && c.timer == nil {
+		c.started = time.Now()
+		c.timer = time.NewTimer(c.remaining)
+	}
+}
+
+// left returns the time remaining until the countdown goes off, zero if it has no time.
+func (c *countdown) left() time.Duration {
+	if c.timer == nil {
+		return c.remaining
+	}
+	if left := c.remaining - time.Since(c.started); left > time.Millisecond {
+		return left
+	}
+	// Never report a running countdown as unlimited
+	return time.Millisecond
+}
+
+// stop stops the countdown, releasing its timer.
+func (c *countdown) stop() {
+	if c.timer != nil {
+		c.timer.Stop()
+	}
+}
@@ -0,0 +1,314 @@
+package model
+
+import (
+	"bufio"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// csvColumns are the columns of a CSV quiz, in the order expected when the file has no header.
+var csvColumns = []string{"question", "answer", "choices", "matcher", "timeout", "points", "explanation", "category", "difficulty", "tags", "markdown", "code", "language", "image"}
+
+// LineError is an error found in a line of a quiz file.
+type LineError struct {
+	// Line of the file, starting at 1
+	Line int
+	// Err is the error found
+	Err error
+}
+
+func (e LineError) Error() string {
+	return fmt.Sprintf("line %d: %v", e.Line, e.Err)
+}
+
+// LineErrors is the error returned when a quiz file has malformed lines, reporting all of them.
+type LineErrors []LineError
+
+func (e LineErrors) Error() string {
+	lines := make([]string, len(e))
+	for i, lineError := range e {
+		lines[i] = lineError.Error()
+	}
+	return "Malformed quiz file:\n  " + strings.Join(lines, "\n  ")
+}
+
+// LoadFromCSV loads a Quiz from a CSV file. See ReadCSV for the details of the format.
+func LoadFromCSV(path string) (*Quiz, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, errors.Wrap(err, "Unable to open CSV file")
+	}
+	defer file.Close()
+
+	return ReadCSV(file)
+}
+
+// ReadCSV reads a Quiz from CSV records which contain the question, the answer and, optionally,
+// the choices of the question, the answer matcher (see ParseMatcher), the time to answer the
+// question, the points it is worth, an explanation of the answer, its category, its
+// difficulty as an Elo rating (see Problem.Difficulty), its tags and its rich content (see
+// Content): whether it is written in Markdown, a code snippet, its language and an image.
+// Several accepted answers, choices or tags are separated by '|', e.g:
+//
+//	What is 5+5?,10,,numeric,5s
+//	Which city is known as the Big Apple?,NYC|New York
+//	Capital of France?,Paris,London|Paris|Rome
+//	Which are prime numbers?,2|3,1|2|3|4
+//
+// The file can start with a header which names the columns, so they can be given in any
+// order and the optional ones can be left out:
+//
+//	question,answer,points,category
+//	What is 5+5?,10,1,arithmetic
+//
+// All the malformed records are reported at once in a LineErrors error.
+func ReadCSV(input io.Reader) (*Quiz, error) {
+	reader := csv.NewReader(bufio.NewReader(input))
+	reader.FieldsPerRecord = -1
+
+	columns := csvColumns
+	var problems []Problem
+	var lineErrors LineErrors
+
+	for first := true; ; first = false {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+
+		line, _ := reader.FieldPos(0)
+		if err != nil {
+			if parseError, ok := err.(*csv.ParseError); ok {
+				line, err = parseError.Line, parseError.Err
+			}
+			lineErrors = append(lineErrors, LineError{line, err})
+			continue
+		}
+
+		if first && isCSVHeader(record) {
+			if columns, err = csvHeader(record); err != nil {
+				lineErrors = append(lineErrors, LineError{line, err})
+				return nil, lineErrors
+			}
+			continue
+		}
+
+		problem, err := csvProblem(columns, record)
+		if err != nil {
+			lineErrors = append(lineErrors, LineError{line, err})
+			continue
+		}
+		problems = append(problems, problem)
+	}
+
+	if len(lineErrors) > 0 {
+		return nil, lineErrors
+	}
+
+	return &Quiz{Problems: problems}, nil
+}
+
+// WriteCSV writes a Quiz as CSV records, with a header naming the columns used by its problems.
+// The settings of the quiz, like its title or its default matcher, can't be written in CSV,
+// nor the problems whose kind can't be inferred or with alternatives which contain '|'.
+func WriteCSV(writer io.Writer, quiz *Quiz) error {
+	records := make([]map[string]string, len(quiz.Problems))
+	used := map[string]bool{"question": true, "answer": true}
+
+	for i := range quiz.Problems {
+		document, err := newProblemDocument(&quiz.Problems[i])
+		if err != nil {
+			return errors.Wrapf(err, "Invalid problem %d", i+1)
+		}
+		if document.Kind != "" {
+			return errors.Errorf("Invalid problem %d: the kind %s can't be given in CSV", i+1, document.Kind)
+		}
+
+		problem := &quiz.Problems[i]
+		record := map[string]string{
+			"question":    document.Question,
+			"matcher":     document.Matcher,
+			"timeout":     document.Timeout,
+			"explanation": document.Explanation,
+			"category":    document.Category,
+			"code":        document.Code,
+			"language":    document.Language,
+			"image":       document.Image,
+		}
+		if record["answer"], err = joinAlternatives(problem.Answers); err != nil {
+			return errors.Wrapf(err, "Invalid problem %d", i+1)
+		}
+		if record["choices"], err = joinAlternatives(problem.Choices); err != nil {
+			return errors.Wrapf(err, "Invalid problem %d", i+1)
+		}
+		if record["tags"], err = joinAlternatives(problem.Tags); err != nil {
+			return errors.Wrapf(err, "Invalid problem %d", i+1)
+		}
+		if problem.Points != 0 {
+			record["points"] = strconv.FormatFloat(problem.Points, 'f', -1, 64)
+		}
+		if problem.Difficulty != 0 {
+			record["difficulty"] = strconv.FormatFloat(problem.Difficulty, 'f', -1, 64)
+		}
+		if problem.Content.Markdown {
+			record["markdown"] = "true"
+		}
+
+		for column, value := range record {
+			used[column] = used[column] || value != ""
+		}
+		records[i] = record
+	}
+
+	var columns []string
+	for _, column := range csvColumns {
+		if used[column] {
+			columns = append(columns, column)
+		}
+	}
+
+	csvWriter := csv.NewWriter(writer)
+	csvWriter.Write(columns)
+	for _, record := range records {
+		fields := make([]string, len(columns))
+		for i, column := range columns {
+			fields[i] = record[column]
+		}
+		csvWriter.Write(fields)
+	}
+
+	csvWriter.Flush()
+	return errors.Wrap(csvWriter.Error(), "Unable to write CSV quiz file")
+}
+
+// joinAlternatives joins several alternatives in a CSV field, separated by '|'.
+func joinAlternatives(alternatives []string) (string, error) {
+	for _, alternative := range alternatives {
+		if strings.Contains(alternative, "|") {
+			return "", errors.Errorf("%q contains '|', which separates alternatives in CSV", alternative)
+		}
+	}
+	return strings.Join(alternatives, "|"), nil
+}
+
+// isCSVHeader checks whether the given record is a header naming the columns: either it starts
+// with the question column or all its fields are names of columns.
+func isCSVHeader(record []string) bool {
+	if strings.EqualFold(strings.TrimSpace(record[0]), "question") {
+		return true
+	}
+	for _, field := range record {
+		name := strings.ToLower(strings.TrimSpace(field))
+		if !isCSVColumn(name) && name != "answers" {
+			return false
+		}
+	}
+	return true
+}
+
+// csvHeader parses the columns named by a header record.
+func csvHeader(record []string) ([]string, error) {
+	columns := make([]string, len(record))
+	seen := make(map[string]bool)
+
+	for i, name := range record {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "answers" {
+			name = "answer"
+		}
+		if !isCSVColumn(name) {
+			return nil, errors.Errorf("Unknown column %q, expected any of %v", record[i], csvColumns)
+		}
+		if seen[name] {
+			return nil, errors.Errorf("Duplicated column %q", record[i])
+		}
+		seen[name] = true
+		columns[i] = name
+	}
+
+	if !seen["answer"] {
+		return nil, errors.New("Missing column \"answer\"")
+	}
+	return columns, nil
+}
+
+func isCSVColumn(name string) bool {
+	for _, column := range csvColumns {
+		if name == column {
+			return true
+		}
+	}
+	return false
+}
+
+// csvProblem builds the Problem described by a CSV record with the given columns.
+func csvProblem(columns []string, record []string) (Problem, error) {
+	if len(record) > len(columns) {
+		return Problem{}, errors.Errorf("Expected at most %d fields, but got %d", len(columns), len(record))
+	}
+
+	fields := make(map[string]string)
+	for i, field := range record {
+		fields[columns[i]] = strings.TrimSpace(field)
+	}
+
+	if fields["question"] == "" {
+		return Problem{}, errors.New("Missing question")
+	}
+	if fields["answer"] == "" {
+		return Problem{}, errors.New("Missing answer")
+	}
+
+	var choices []string
+	if fields["choices"] != "" {
+		choices = splitAlternatives(fields["choices"])
+	}
+
+	problem := NewProblem(fields["question"], splitAlternatives(fields["answer"]), choices)
+	problem.Explanation = fields["explanation"]
+	problem.Category = fields["category"]
+	problem.Content = Content{Code: fields["code"], Language: fields["language"], Image: fields["image"]}
+	if fields["tags"] != "" {
+		problem.Tags = splitAlternatives(fields["tags"])
+	}
+
+	var err error
+	if problem.Matcher, err = parseOptionalMatcher(fields["matcher"]); err != nil {
+		return Problem{}, err
+	}
+	if problem.Timeout, err = parseOptionalDuration(fields["timeout"]); err != nil {
+		return Problem{}, err
+	}
+	if fields["points"] != "" {
+		if problem.Points, err = parsePoints(fields["points"]); err != nil {
+			return Problem{}, err
+		}
+	}
+	if fields["difficulty"] != "" {
+		if problem.Difficulty, err = strconv.ParseFloat(fields["difficulty"], 64); err != nil {
+			return Problem{}, errors.Errorf("Invalid difficulty %q", fields["difficulty"])
+		}
+	}
+	if fields["markdown"] != "" {
+		if problem.Content.Markdown, err = strconv.ParseBool(fields["markdown"]); err != nil {
+			return Problem{}, errors.Errorf("Invalid markdown %q, expected true or false", fields["markdown"])
+		}
+	}
+
+	return problem, nil
+}
+
+// splitAlternatives splits a CSV field which contains several alternatives separated by '|'.
+func splitAlternatives(field string) []string {
+	alternatives := strings.Split(field, "|")
+	for i, alternative := range alternatives {
+		alternatives[i] = strings.TrimSpace(alternative)
+	}
+	return alternatives
+}
@@ -0,0 +1,85 @@
+package model
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestReadCSVWithoutHeader(t *testing.T) {
+	csv := "5+5,10\nCapital of France?,Paris,London|Paris,fold,5s,2,It is Paris,geography\n"
+
+	france := NewProblem("Capital of France?", []string{"Paris"}, []string{"London", "Paris"})
+	france.Matcher = CaseInsensitive{}
+	france.Timeout = 5 * time.Second
+	france.Points = 2
+	france.Explanation = "It is Paris"
+	france.Category = "geography"
+	expected := &Quiz{Problems: []Problem{NewProblem("5+5", []string{"10"}, nil), france}}
+
+	quiz, err := ReadCSV(strings.NewReader(csv))
+
+	if err != nil {
+		t.Fatalf("Expected valid result, but an error was returned: %+v", err)
+	}
+	if !reflect.DeepEqual(quiz, expected) {
+		t.Errorf("Expected quiz %+v, but got %+v", expected, quiz)
+	}
+}
+
+func TestReadCSVWithHeader(t *testing.T) {
+	csv := "Question,Category,Answer,Tags\n5+5,arithmetic,10,easy|sums\n"
+
+	problem := NewProblem("5+5", []string{"10"}, nil)
+	problem.Category = "arithmetic"
+	problem.Tags = []string{"easy", "sums"}
+	expected := &Quiz{Problems: []Problem{problem}}
+
+	quiz, err := ReadCSV(strings.NewReader(csv))
+
+	if err != nil {
+		t.Fatalf("Expected valid result, but an error was returned: %+v", err)
+	}
+	if !reflect.DeepEqual(quiz, expected) {
+		t.Errorf("Expected quiz %+v, but got %+v", expected, quiz)
+	}
+}
+
+func TestReadCSVWithReorderedHeader(t *testing.T) {
+	expected := &Quiz{Problems: []Problem{NewProblem("5+5", []string{"10"}, nil)}}
+
+	quiz, err := ReadCSV(strings.NewReader("Answer,Question\n10,5+5\n"))
+
+	if err != nil {
+		t.Fatalf("Expected valid result, but an error was returned: %+v", err)
+	}
+	if !reflect.DeepEqual(quiz, expected) {
+		t.Errorf("Expected quiz %+v, but got %+v", expected, quiz)
+	}
+}
+
+func TestReadCSVReportsAllMalformedLines(t *testing.T) {
+	csv := "5+5,10\n1+1\n2+2,4,,unknown\n3+3,6\n,7\n4+4,8,,,,,,,,,,,,,extra\n6+6,12,,,,-1\n7+7,14,,,,NaN\n"
+
+	_, err := ReadCSV(strings.NewReader(csv))
+
+	lineErrors, ok := err.(LineErrors)
+	if !ok {
+		t.Fatalf("Expected LineErrors, but got %+v", err)
+	}
+
+	lines := make([]int, len(lineErrors))
+	for i, lineError := range lineErrors {
+		lines[i] = lineError.Line
+	}
+	if expected := []int{2, 3, 5, 6, 7, 8}; !reflect.DeepEqual(lines, expected) {
+		t.Errorf("Expected errors in lines %v, but got %v", expected, err)
+	}
+}
+
+func TestReadCSVRejectsUnknownHeaderColumns(t *testing.T) {
+	if _, err := ReadCSV(strings.NewReader("question,answer,author\n5+5,10,me\n")); err == nil {
+		t.Error("Expected an error for an unknown column")
+	}
+}
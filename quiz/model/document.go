@@ -0,0 +1,318 @@
+package model
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"github.com/pkg/errors"
+	yaml "gopkg.in/yaml.v2"
+)
+
+func init() {
+	RegisterFormat(Format{Name: "csv", Extensions: []string{".csv"}, Load: ReadCSV, Save: WriteCSV})
+	RegisterFormat(Format{Name: "json", Extensions: []string{".json"}, Load: readJSON, Save: writeJSON})
+	RegisterFormat(Format{Name: "yaml", Extensions: []string{".yaml", ".yml"}, Load: readYAML, Save: writeYAML})
+	RegisterFormat(Format{Name: "toml", Extensions: []string{".toml"}, Load: readTOML, Save: writeTOML})
+}
+
+// quizDocument is the representation of a Quiz in structured formats like JSON, YAML or TOML:
+//
+//	title: Capitals
+//	matcher: fold
+//	questionTimeout: 10s
+//	penalty: 0.25
+//	partialCredit: true
+//	passMark: 60
+//	adaptive: true
+//	feedback: true
+//	minAnswerTime: 1s
+//	problems:
+//	  - question: Which city is known as the Big Apple?
+//	    answers: [NYC, New York]
+//	    points: 2
+//	    explanation: It was popularized by a New York Morning Telegraph columnist.
+//	    tags: [usa]
+//	    difficulty: 1600
+//	  - question: Capital of France?
+//	    answer: Paris
+//	    choices: [London, Paris, Rome]
+//	    timeout: 5s
+//	  - question: What does the **program** print?
+//	    markdown: true
+//	    code: fmt.Println(5 + 5)
+//	    language: go
+//	    answer: "10"
+//	  - question: Which country is this?
+//	    image: images/spain.png
+//	    answer: Spain
+type quizDocument struct {
+	Title           string            `json:"title,omitempty" yaml:"title,omitempty" toml:"title,omitempty"`
+	Matcher         string            `json:"matcher,omitempty" yaml:"matcher,omitempty" toml:"matcher,omitempty"`
+	QuestionTimeout string            `json:"questionTimeout,omitempty" yaml:"questionTimeout,omitempty" toml:"questionTimeout,omitempty"`
+	Penalty         float64           `json:"penalty,omitempty" yaml:"penalty,omitempty" toml:"penalty,omitzero"`
+	PartialCredit   bool              `json:"partialCredit,omitempty" yaml:"partialCredit,omitempty" toml:"partialCredit,omitempty"`
+	PassMark        float64           `json:"passMark,omitempty" yaml:"passMark,omitempty" toml:"passMark,omitzero"`
+	Adaptive        bool              `json:"adaptive,omitempty" yaml:"adaptive,omitempty" toml:"adaptive,omitempty"`
+	Feedback        bool              `json:"feedback,omitempty" yaml:"feedback,omitempty" toml:"feedback,omitempty"`
+	MinAnswerTime   string            `json:"minAnswerTime,omitempty" yaml:"minAnswerTime,omitempty" toml:"minAnswerTime,omitempty"`
+	Problems        []problemDocument `json:"problems" yaml:"problems" toml:"problems"`
+}
+
+// problemDocument is the representation of a Problem in structured formats. A single accepted
+// answer can be given with "answer" instead of "answers", and the kind is inferred as in
+// NewProblem unless it is given.
+type problemDocument struct {
+	Kind        string   `json:"kind,omitempty" yaml:"kind,omitempty" toml:"kind,omitempty"`
+	Question    string   `json:"question" yaml:"question" toml:"question"`
+	Answer      string   `json:"answer,omitempty" yaml:"answer,omitempty" toml:"answer,omitempty"`
+	Answers     []string `json:"answers,omitempty" yaml:"answers,omitempty" toml:"answers,omitempty"`
+	Choices     []string `json:"choices,omitempty" yaml:"choices,omitempty" toml:"choices,omitempty"`
+	Matcher     string   `json:"matcher,omitempty" yaml:"matcher,omitempty" toml:"matcher,omitempty"`
+	Timeout     string   `json:"timeout,omitempty" yaml:"timeout,omitempty" toml:"timeout,omitempty"`
+	Points      float64  `json:"points,omitempty" yaml:"points,omitempty" toml:"points,omitzero"`
+	Explanation string   `json:"explanation,omitempty" yaml:"explanation,omitempty" toml:"explanation,omitempty"`
+	Category    string   `json:"category,omitempty" yaml:"category,omitempty" toml:"category,omitempty"`
+	Tags        []string `json:"tags,omitempty" yaml:"tags,omitempty" toml:"tags,omitempty"`
+	Difficulty  float64  `json:"difficulty,omitempty" yaml:"difficulty,omitempty" toml:"difficulty,omitzero"`
+	Markdown    bool     `json:"markdown,omitempty" yaml:"markdown,omitempty" toml:"markdown,omitempty"`
+	Code        string   `json:"code,omitempty" yaml:"code,omitempty" toml:"code,omitempty"`
+	Language    string   `json:"language,omitempty" yaml:"language,omitempty" toml:"language,omitempty"`
+	Image       string   `json:"image,omitempty" yaml:"image,omitempty" toml:"image,omitempty"`
+}
+
+// readJSON reads a Quiz from its JSON representation.
+func readJSON(reader io.Reader) (*Quiz, error) {
+	var document quizDocument
+	if err := json.NewDecoder(reader).Decode(&document); err != nil {
+		return nil, errors.Wrap(err, "Malformed JSON quiz file")
+	}
+	return document.quiz()
+}
+
+// readYAML reads a Quiz from its YAML representation.
+func readYAML(reader io.Reader) (*Quiz, error) {
+	var document quizDocument
+	if err := yaml.NewDecoder(reader).Decode(&document); err != nil {
+		return nil, errors.Wrap(err, "Malformed YAML quiz file")
+	}
+	return document.quiz()
+}
+
+// readTOML reads a Quiz from its TOML representation, where problems are an array of tables.
+func readTOML(reader io.Reader) (*Quiz, error) {
+	var document quizDocument
+	if _, err := toml.DecodeReader(reader, &document); err != nil {
+		return nil, errors.Wrap(err, "Malformed TOML quiz file")
+	}
+	return document.quiz()
+}
+
+// writeJSON writes the JSON representation of a Quiz, indented.
+func writeJSON(writer io.Writer, quiz *Quiz) error {
+	document, err := newQuizDocument(quiz)
+	if err != nil {
+		return err
+	}
+
+	encoder := json.NewEncoder(writer)
+	encoder.SetIndent("", "  ")
+	return errors.Wrap(encoder.Encode(document), "Unable to write JSON quiz file")
+}
+
+// writeYAML writes the YAML representation of a Quiz.
+func writeYAML(writer io.Writer, quiz *Quiz) error {
+	document, err := newQuizDocument(quiz)
+	if err != nil {
+		return err
+	}
+
+	encoder := yaml.NewEncoder(writer)
+	if err := encoder.Encode(document); err != nil {
+		return errors.Wrap(err, "Unable to write YAML quiz file")
+	}
+	return errors.Wrap(encoder.Close(), "Unable to write YAML quiz file")
+}
+
+// writeTOML writes the TOML representation of a Quiz.
+func writeTOML(writer io.Writer, quiz *Quiz) error {
+	document, err := newQuizDocument(quiz)
+	if err != nil {
+		return err
+	}
+	return errors.Wrap(toml.NewEncoder(writer).Encode(document), "Unable to write TOML quiz file")
+}
+
+// quiz builds the Quiz described by the document, validating its contents.
+func (d *quizDocument) quiz() (*Quiz, error) {
+	quiz := &Quiz{
+		Title:    d.Title,
+		Problems: make([]Problem, len(d.Problems)),
+		Scoring:  Scoring{Penalty: d.Penalty, PartialCredit: d.PartialCredit, PassMark: d.PassMark},
+		Adaptive: d.Adaptive,
+		Feedback: d.Feedback,
+	}
+
+	if err := quiz.Scoring.Validate(); err != nil {
+		return nil, err
+	}
+
+	var err error
+	if quiz.Matcher, err = parseOptionalMatcher(d.Matcher); err != nil {
+		return nil, err
+	}
+	if quiz.QuestionTimeout, err = parseOptionalDuration(d.QuestionTimeout); err != nil {
+		return nil, err
+	}
+	if quiz.MinAnswerTime, err = parseOptionalDuration(d.MinAnswerTime); err != nil {
+		return nil, err
+	}
+
+	for i, problemDoc := range d.Problems {
+		if quiz.Problems[i], err = problemDoc.problem(); err != nil {
+			return nil, errors.Wrapf(err, "Invalid problem %d", i+1)
+		}
+	}
+
+	return quiz, nil
+}
+
+// problem builds the Problem described by the document, validating its contents.
+func (d *problemDocument) problem() (Problem, error) {
+	if strings.TrimSpace(d.Question) == "" {
+		return Problem{}, errors.New("Missing question")
+	}
+
+	answers := d.Answers
+	if d.Answer != "" {
+		answers = append([]string{d.Answer}, answers...)
+	}
+	if len(answers) == 0 {
+		return Problem{}, errors.New("Missing answer")
+	}
+
+	problem := NewProblem(d.Question, answers, d.Choices)
+	problem.Points = d.Points
+	problem.Explanation = d.Explanation
+	problem.Category = d.Category
+	problem.Tags = d.Tags
+	problem.Difficulty = d.Difficulty
+	problem.Content = Content{Markdown: d.Markdown, Code: d.Code, Language: d.Language, Image: d.Image}
+
+	if err := checkPoints(d.Points); err != nil {
+		return Problem{}, err
+	}
+
+	var err error
+	if d.Kind != "" {
+		if problem.Kind, err = ParseKind(d.Kind); err != nil {
+			return Problem{}, err
+		}
+	}
+	if problem.Matcher, err = parseOptionalMatcher(d.Matcher); err != nil {
+		return Problem{}, err
+	}
+	if problem.Timeout, err = parseOptionalDuration(d.Timeout); err != nil {
+		return Problem{}, err
+	}
+
+	return problem, nil
+}
+
+// newQuizDocument builds the document which describes the given Quiz.
+func newQuizDocument(q *Quiz) (quizDocument, error) {
+	document := quizDocument{
+		Title:         q.Title,
+		Penalty:       q.Scoring.Penalty,
+		PartialCredit: q.Scoring.PartialCredit,
+		PassMark:      q.Scoring.PassMark,
+		Adaptive:      q.Adaptive,
+		Feedback:      q.Feedback,
+		Problems:      make([]problemDocument, len(q.Problems)),
+	}
+
+	var err error
+	if document.Matcher, err = formatOptionalMatcher(q.Matcher); err != nil {
+		return quizDocument{}, err
+	}
+	if q.QuestionTimeout > 0 {
+		document.QuestionTimeout = q.QuestionTimeout.String()
+	}
+	if q.MinAnswerTime > 0 {
+		document.MinAnswerTime = q.MinAnswerTime.String()
+	}
+
+	for i := range q.Problems {
+		if document.Problems[i], err = newProblemDocument(&q.Problems[i]); err != nil {
+			return quizDocument{}, errors.Wrapf(err, "Invalid problem %d", i+1)
+		}
+	}
+	return document, nil
+}
+
+// newProblemDocument builds the document which describes the given Problem. The kind is
+// only given when it can't be inferred.
+func newProblemDocument(p *Problem) (problemDocument, error) {
+	document := problemDocument{
+		Question:    p.Question,
+		Answers:     p.Answers,
+		Choices:     p.Choices,
+		Points:      p.Points,
+		Explanation: p.Explanation,
+		Category:    p.Category,
+		Tags:        p.Tags,
+		Difficulty:  p.Difficulty,
+		Markdown:    p.Content.Markdown,
+		Code:        p.Content.Code,
+		Language:    p.Content.Language,
+		Image:       p.Content.Image,
+	}
+
+	if len(p.Answers) == 1 {
+		document.Answer, document.Answers = p.Answers[0], nil
+	}
+	if inferred := NewProblem(p.Question, p.Answers, p.Choices); inferred.Kind != p.Kind {
+		document.Kind = p.Kind.String()
+	}
+
+	var err error
+	if document.Matcher, err = formatOptionalMatcher(p.Matcher); err != nil {
+		return problemDocument{}, err
+	}
+	if p.Timeout > 0 {
+		document.Timeout = p.Timeout.String()
+	}
+	return document, nil
+}
+
+// formatOptionalMatcher returns the specification of a Matcher, as understood by ParseMatcher,
+// or empty if it is nil. Matchers without specification can't be described.
+func formatOptionalMatcher(matcher Matcher) (string, error) {
+	if matcher == nil {
+		return "", nil
+	}
+	if stringer, ok := matcher.(fmt.Stringer); ok {
+		return stringer.String(), nil
+	}
+	return "", errors.Errorf("Unable to describe matcher %T", matcher)
+}
+
+// parseOptionalMatcher parses a Matcher specification, returning nil if it is empty.
+func parseOptionalMatcher(spec string) (Matcher, error) {
+	if strings.TrimSpace(spec) == "" {
+		return nil, nil
+	}
+	return ParseMatcher(spec)
+}
+
+// parseOptionalDuration parses a duration like "10s", returning zero if it is empty.
+func parseOptionalDuration(duration string) (time.Duration, error) {
+	if strings.TrimSpace(duration) == "" {
+		return 0, nil
+	}
+	parsed, err := time.ParseDuration(strings.TrimSpace(duration))
+	return parsed, errors.Wrapf(err, "Invalid duration %q", duration)
+}
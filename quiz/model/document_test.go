@@ -0,0 +1,181 @@
+package model
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func expectedDocumentQuiz() *Quiz {
+	bigApple := NewProblem("Big Apple?", []string{"NYC", "New York"}, nil)
+	bigApple.Points = 2
+	bigApple.Explanation = "Popularized in the 1920s"
+	bigApple.Tags = []string{"usa"}
+
+	france := NewProblem("Capital of France?", []string{"Paris"}, []string{"London", "Paris"})
+	france.Matcher = CaseInsensitive{}
+	france.Timeout = 5 * time.Second
+
+	return &Quiz{
+		Title:           "Capitals",
+		Matcher:         Fuzzy{1},
+		QuestionTimeout: 10 * time.Second,
+		Problems:        []Problem{bigApple, france},
+	}
+}
+
+func documentTest(t *testing.T, formatName, document string) {
+	t.Helper()
+	format, err := FormatFor("", formatName)
+	if err != nil {
+		t.Fatalf("Expected format %s to be registered, but an error was returned: %+v", formatName, err)
+	}
+
+	quiz, err := format.Load(strings.NewReader(document))
+	if err != nil {
+		t.Fatalf("Expected valid result, but an error was returned: %+v", err)
+	}
+
+	if expected := expectedDocumentQuiz(); !reflect.DeepEqual(quiz, expected) {
+		t.Errorf("Expected quiz %+v, but got %+v", expected, quiz)
+	}
+}
+
+func TestLoadJSON(t *testing.T) {
+	documentTest(t, "json", `{
+		"title": "Capitals",
+		"matcher": "fuzzy",
+		"questionTimeout": "10s",
+		"problems": [
+			{"question": "Big Apple?", "answers": ["NYC", "New York"], "points": 2, "explanation": "Popularized in the 1920s", "tags": ["usa"]},
+			{"question": "Capital of France?", "answer": "Paris", "choices": ["London", "Paris"], "matcher": "fold", "timeout": "5s"}
+		]
+	}`)
+}
+
+func TestLoadYAML(t *testing.T) {
+	documentTest(t, "yaml", `
+title: Capitals
+matcher: fuzzy
+questionTimeout: 10s
+problems:
+  - question: Big Apple?
+    answers: [NYC, New York]
+    points: 2
+    explanation: Popularized in the 1920s
+    tags: [usa]
+  - question: Capital of France?
+    answer: Paris
+    choices: [London, Paris]
+    matcher: fold
+    timeout: 5s
+`)
+}
+
+func TestLoadTOML(t *testing.T) {
+	documentTest(t, "toml", `
+title = "Capitals"
+matcher = "fuzzy"
+questionTimeout = "10s"
+
+[[problems]]
+question = "Big Apple?"
+answers = ["NYC", "New York"]
+points = 2.0
+explanation = "Popularized in the 1920s"
+tags = ["usa"]
+
+[[problems]]
+question = "Capital of France?"
+answer = "Paris"
+choices = ["London", "Paris"]
+matcher = "fold"
+timeout = "5s"
+`)
+}
+
+func TestLoadRejectsProblemsWithoutAnswer(t *testing.T) {
+	format, _ := FormatFor("quiz.yml", "")
+
+	if _, err := format.Load(strings.NewReader("problems:\n  - question: 5+5\n")); err == nil {
+		t.Error("Expected an error for a problem without answer")
+	}
+}
+
+func TestLoadRejectsInvalidScoring(t *testing.T) {
+	format, _ := FormatFor("quiz.yml", "")
+
+	for _, document := range []string{
+		"problems:\n  - question: 5+5\n    answer: \"10\"\n    points: -1\n",
+		"problems:\n  - question: 5+5\n    answer: \"10\"\n    points: .nan\n",
+		"penalty: 1.5\nproblems:\n  - question: 5+5\n    answer: \"10\"\n",
+		"passMark: -10\nproblems:\n  - question: 5+5\n    answer: \"10\"\n",
+	} {
+		if _, err := format.Load(strings.NewReader(document)); err == nil {
+			t.Errorf("Expected an error for invalid scoring in %q", document)
+		}
+	}
+}
+
+func TestFormatForGuessesFromExtension(t *testing.T) {
+	for path, expected := range map[string]string{"quiz.csv": "csv", "quiz.JSON": "json", "quiz.yml": "yaml", "quiz.toml": "toml"} {
+		format, err := FormatFor(path, "")
+		if err != nil || format.Name != expected {
+			t.Errorf("Expected format %s for %s, but got %s (%v)", expected, path, format.Name, err)
+		}
+	}
+
+	if _, err := FormatFor("quiz.txt", ""); err == nil {
+		t.Error("Expected an error for an unknown extension")
+	}
+}
+
+func TestSaveAndLoadRoundTrip(t *testing.T) {
+	for _, name := range Formats() {
+		// The formats of LMSs can't represent every setting, so they have their own tests
+		if name == "gift" || name == "qti" {
+			continue
+		}
+		format, _ := FormatFor("", name)
+		expected := expectedDocumentQuiz()
+		expected.Problems[0].Content = Content{Markdown: true, Code: "fmt.Println(5 + 5)", Language: "go", Image: "gopher.png"}
+		if format.Name == "csv" {
+			expected.Title, expected.Matcher, expected.QuestionTimeout = "", nil, 0
+		}
+
+		var buffer bytes.Buffer
+		if err := format.Save(&buffer, expected); err != nil {
+			t.Fatalf("Expected %s quiz to be written, but an error was returned: %+v", format.Name, err)
+		}
+
+		quiz, err := format.Load(&buffer)
+		if err != nil {
+			t.Fatalf("Expected written %s quiz to be loaded, but an error was returned: %+v", format.Name, err)
+		}
+		if !reflect.DeepEqual(quiz, expected) {
+			t.Errorf("Expected %s quiz %+v, but got %+v", format.Name, expected, quiz)
+		}
+	}
+}
+
+func TestReplaceFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "quiz.json")
+	if err := os.WriteFile(path, []byte("previous"), 0644); err != nil {
+		t.Fatalf("Expected the file to be created, but an error was returned: %+v", err)
+	}
+
+	if err := ReplaceFile(path, []byte("replaced")); err != nil {
+		t.Fatalf("Expected the file to be replaced, but an error was returned: %+v", err)
+	}
+
+	if data, err := os.ReadFile(path); err != nil || string(data) != "replaced" {
+		t.Errorf("Expected contents 'replaced', but got %q (%v)", data, err)
+	}
+	if _, err := os.Stat(path + ".tmp"); !os.IsNotExist(err) {
+		t.Errorf("Expected the temporary file to be gone, but got %v", err)
+	}
+}
@@ -0,0 +1,546 @@
+package model
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+func init() {
+	RegisterFormat(Format{Name: "gift", Extensions: []string{".gift"}, Load: ReadGIFT, Save: WriteGIFT})
+}
+
+// giftSpecial are the characters escaped with a backslash in GIFT text.
+const giftSpecial = `~=#{}:\`
+
+// ReadGIFT reads a Quiz in the Moodle GIFT format, where questions are separated by blank lines
+// and their answers are given between braces:
+//
+//	// Comments are ignored
+//	$CATEGORY: geography
+//
+//	::Capital:: Capital of France? {=Paris ~London ~Rome #### Paris is on the Seine}
+//
+//	Which are prime numbers? {~%50%2 ~%50%3 ~%-100%4}
+//
+//	What is 5+5? {#10:0.5}
+//
+//	The sun is a star. {T}
+//
+//	Which city is known as the Big Apple? {=NYC =New York}
+//
+// Answers starting with '=' are correct and the ones starting with '~' are wrong, unless they
+// have a positive weight, which makes the problem MultipleAnswer. Numeric answers are compared
+// with a Numeric matcher and short answers ignoring case, as LMSs do. True-false questions are
+// choice problems with the choices "True" and "False", and the text after the answers of a
+// missing word question is appended to the question after a blank. The general feedback, after "####", is the explanation, and "$CATEGORY:" sets
+// the category of the following questions. Titles and the feedback of each answer are ignored,
+// and so are the formats of the questions other than "[markdown]".
+//
+// All the malformed questions are reported at once in a LineErrors error.
+func ReadGIFT(input io.Reader) (*Quiz, error) {
+	scanner := bufio.NewScanner(input)
+	var problems []Problem
+	var lineErrors LineErrors
+	var category string
+
+	var block []string
+	start := 0
+	flush := func() {
+		if len(block) == 0 {
+			return
+		}
+		text := strings.TrimSpace(strings.Join(block, "\n"))
+		block = nil
+
+		problem, err := giftProblem(text)
+		if err != nil {
+			lineErrors = append(lineErrors, LineError{start, err})
+			return
+		}
+		problem.Category = category
+		problems = append(problems, problem)
+	}
+
+	for line := 1; scanner.Scan(); line++ {
+		text := scanner.Text()
+		switch trimmed := strings.TrimSpace(text); {
+		case trimmed == "":
+			flush()
+		case strings.HasPrefix(trimmed, "//"):
+		case strings.HasPrefix(trimmed, "$CATEGORY:"):
+			flush()
+			category = giftCategory(strings.TrimPrefix(trimmed, "$CATEGORY:"))
+		default:
+			if len(block) == 0 {
+				start = line
+			}
+			block = append(block, text)
+		}
+	}
+	flush()
+
+	if err := scanner.Err(); err != nil {
+		return nil, errors.Wrap(err, "Unable to read GIFT quiz file")
+	}
+	if len(lineErrors) > 0 {
+		return nil, lineErrors
+	}
+
+	return &Quiz{Problems: problems}, nil
+}
+
+// giftCategory returns the category set by a "$CATEGORY:" command, which is the last component
+// of the path of categories of Moodle, e.g. "arithmetic" for "$course$/Maths/arithmetic".
+func giftCategory(path string) string {
+	path = strings.TrimSpace(path)
+	return strings.TrimSpace(path[strings.LastIndex(path, "/")+1:])
+}
+
+// giftProblem builds the Problem described by a GIFT question.
+func giftProblem(text string) (Problem, error) {
+	if strings.HasPrefix(text, "::") {
+		end := indexUnescaped(text[2:], "::")
+		if end < 0 {
+			return Problem{}, errors.New("Unclosed title")
+		}
+		text = strings.TrimSpace(text[2+end+2:])
+	}
+
+	markdown := false
+	if strings.HasPrefix(text, "[") {
+		if end := strings.Index(text, "]"); end > 0 {
+			switch format := strings.ToLower(text[1:end]); format {
+			case "markdown", "html", "plain", "moodle":
+				markdown = format == "markdown"
+				text = text[end+1:]
+			}
+		}
+	}
+
+	open := indexUnescaped(text, "{")
+	if open < 0 {
+		return Problem{}, errors.New("Missing answer")
+	}
+	end := indexUnescaped(text[open:], "}")
+	if end < 0 {
+		return Problem{}, errors.New("Unclosed answer")
+	}
+	end += open
+
+	question := strings.TrimSpace(giftUnescape(text[:open]))
+	if after := strings.TrimSpace(giftUnescape(text[end+1:])); after != "" {
+		question = strings.TrimSpace(question + " _____ " + after)
+	}
+	if question == "" {
+		return Problem{}, errors.New("Missing question")
+	}
+
+	answers, explanation := text[open+1:end], ""
+	if i := indexUnescaped(answers, "####"); i >= 0 {
+		answers, explanation = answers[:i], strings.TrimSpace(giftUnescape(answers[i+4:]))
+	}
+
+	problem, err := giftAnswers(question, strings.TrimSpace(answers))
+	if err != nil {
+		return Problem{}, err
+	}
+	problem.Explanation = explanation
+	problem.Content.Markdown = markdown
+	return problem, nil
+}
+
+// giftAnswers builds the Problem for the given question from the answers of a GIFT question.
+func giftAnswers(question, answers string) (Problem, error) {
+	if answers == "" {
+		return Problem{}, errors.New("Essay questions can't be checked, expected some answers")
+	}
+	if strings.HasPrefix(answers, "#") {
+		return giftNumeric(question, answers[1:])
+	}
+
+	switch strings.ToUpper(giftFeedback(answers)) {
+	case "T", "TRUE":
+		return NewProblem(question, []string{"True"}, []string{"True", "False"}), nil
+	case "F", "FALSE":
+		return NewProblem(question, []string{"False"}, []string{"True", "False"}), nil
+	}
+
+	var correct, choices []string
+	wrong := false
+	for _, option := range splitUnescaped(answers, "=~") {
+		mark, text := option[0], option[1:]
+		if indexUnescaped(text, "->") >= 0 {
+			return Problem{}, errors.New("Matching questions are not supported")
+		}
+
+		weight, text, err := giftWeight(text)
+		if err != nil {
+			return Problem{}, err
+		}
+		text = strings.TrimSpace(giftUnescape(giftFeedback(text)))
+		if text == "" {
+			return Problem{}, errors.New("Empty answer")
+		}
+
+		choices = append(choices, text)
+		if mark == '=' || weight > 0 {
+			correct = append(correct, text)
+		} else {
+			wrong = true
+		}
+	}
+
+	if len(correct) == 0 {
+		return Problem{}, errors.New("Missing correct answer")
+	}
+	if !wrong {
+		problem := NewProblem(question, correct, nil)
+		problem.Matcher = CaseInsensitive{}
+		return problem, nil
+	}
+	return NewProblem(question, correct, choices), nil
+}
+
+// giftNumeric builds the Open problem for the answers of a GIFT numeric question, either a single
+// answer with its tolerance ("10:0.5"), a range ("9.5..10.5") or several alternatives of those,
+// each one starting with '='. The problem is checked with the largest tolerance of its answers.
+func giftNumeric(question, answers string) (Problem, error) {
+	alternatives := []string{giftFeedback(answers)}
+	if strings.HasPrefix(strings.TrimSpace(answers), "=") {
+		alternatives = nil
+		for _, option := range splitUnescaped(answers, "=") {
+			alternatives = append(alternatives, giftFeedback(option[1:]))
+		}
+	}
+
+	var values []string
+	tolerance := 0.0
+	for _, alternative := range alternatives {
+		_, alternative, err := giftWeight(alternative)
+		if err != nil {
+			return Problem{}, err
+		}
+		value, margin, err := giftNumber(strings.TrimSpace(alternative))
+		if err != nil {
+			return Problem{}, err
+		}
+		values = append(values, value)
+		tolerance = math.Max(tolerance, margin)
+	}
+
+	problem := NewProblem(question, values, nil)
+	problem.Matcher = Numeric{tolerance}
+	return problem, nil
+}
+
+// giftNumber parses a numeric answer of GIFT, returning the answer and its tolerance.
+func giftNumber(answer string) (string, float64, error) {
+	if i := strings.Index(answer, ".."); i >= 0 {
+		low, lowErr := strconv.ParseFloat(strings.TrimSpace(answer[:i]), 64)
+		high, highErr := strconv.ParseFloat(strings.TrimSpace(answer[i+2:]), 64)
+		if lowErr != nil || highErr != nil || low > high {
+			return "", 0, errors.Errorf("Invalid numeric range %q", answer)
+		}
+		return strconv.FormatFloat(roundFloat((low+high)/2), 'f', -1, 64), roundFloat((high - low) / 2), nil
+	}
+
+	value, margin := answer, ""
+	if i := strings.Index(answer, ":"); i >= 0 {
+		value, margin = strings.TrimSpace(answer[:i]), strings.TrimSpace(answer[i+1:])
+	}
+	if _, err := strconv.ParseFloat(value, 64); err != nil {
+		return "", 0, errors.Errorf("Invalid numeric answer %q", answer)
+	}
+	if margin == "" {
+		return value, 0, nil
+	}
+	tolerance, err := strconv.ParseFloat(margin, 64)
+	if err != nil || tolerance < 0 {
+		return "", 0, errors.Errorf("Invalid numeric tolerance %q", margin)
+	}
+	return value, tolerance, nil
+}
+
+// roundFloat rounds a value to 12 significant digits, dropping the error of the floating point
+// arithmetic done on decimal numbers.
+func roundFloat(value float64) float64 {
+	rounded, _ := strconv.ParseFloat(strconv.FormatFloat(value, 'g', 12, 64), 64)
+	return rounded
+}
+
+// giftWeight parses the optional weight of a GIFT answer, as a percentage between '%', returning
+// the weight and the rest of the answer. Answers without weight weigh 0.
+func giftWeight(answer string) (float64, string, error) {
+	answer = strings.TrimSpace(answer)
+	if !strings.HasPrefix(answer, "%") {
+		return 0, answer, nil
+	}
+	end := strings.Index(answer[1:], "%")
+	if end < 0 {
+		return 0, "", errors.Errorf("Unclosed weight in %q", answer)
+	}
+	weight, err := strconv.ParseFloat(answer[1:1+end], 64)
+	if err != nil {
+		return 0, "", errors.Errorf("Invalid weight %q", answer[1:1+end])
+	}
+	return weight, answer[2+end:], nil
+}
+
+// giftFeedback strips the feedback of a GIFT answer, which follows an unescaped '#'.
+func giftFeedback(answer string) string {
+	if i := indexUnescaped(answer, "#"); i >= 0 {
+		answer = answer[:i]
+	}
+	return strings.TrimSpace(answer)
+}
+
+// indexUnescaped returns the index of the first occurrence of substr in s which isn't escaped
+// with a backslash, or -1 if there is none.
+func indexUnescaped(s, substr string) int {
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\\' {
+			i++
+			continue
+		}
+		if strings.HasPrefix(s[i:], substr) {
+			return i
+		}
+	}
+	return -1
+}
+
+// splitUnescaped splits s before each unescaped occurrence of any of the given marks, so each part
+// starts with its mark. Anything before the first mark is dropped.
+func splitUnescaped(s, marks string) []string {
+	var parts []string
+	start := -1
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\\' {
+			i++
+			continue
+		}
+		if strings.IndexByte(marks, s[i]) >= 0 {
+			if start >= 0 {
+				parts = append(parts, s[start:i])
+			}
+			start = i
+		}
+	}
+	if start >= 0 {
+		parts = append(parts, s[start:])
+	}
+	return parts
+}
+
+// giftUnescape resolves the escaped characters and new lines of GIFT text.
+func giftUnescape(text string) string {
+	var unescaped strings.Builder
+	for i := 0; i < len(text); i++ {
+		if text[i] == '\\' && i+1 < len(text) {
+			i++
+			if text[i] == 'n' {
+				unescaped.WriteByte('\n')
+				continue
+			}
+		}
+		unescaped.WriteByte(text[i])
+	}
+	return unescaped.String()
+}
+
+// giftEscape escapes the special characters and new lines of GIFT text.
+func giftEscape(text string) string {
+	var escaped strings.Builder
+	for _, r := range text {
+		switch {
+		case r == '\n':
+			escaped.WriteString(`\n`)
+		case strings.ContainsRune(giftSpecial, r):
+			escaped.WriteByte('\\')
+			escaped.WriteRune(r)
+		default:
+			escaped.WriteRune(r)
+		}
+	}
+	return escaped.String()
+}
+
+// WriteGIFT writes a Quiz in the Moodle GIFT format (see ReadGIFT), with the title of the quiz
+// as a comment. Numeric problems are written as numeric questions, choice problems between
+// "True" and "False" as true-false questions and MultipleAnswer problems with weights which
+// split the points among the correct choices and take them all for a wrong one. Code snippets and
+// images are written in Markdown along with the question. The settings of the quiz, and the time
+// to answer, the points, the tags and the difficulty of the problems, can't be written in GIFT,
+// nor the problems whose answers are compared with regular expressions or fuzzy matching, which
+// LMSs compare literally ignoring case, or whose correct choices are ambiguous.
+func WriteGIFT(writer io.Writer, quiz *Quiz) error {
+	output := bufio.NewWriter(writer)
+	if quiz.Title != "" {
+		fmt.Fprintf(output, "// %s\n\n", strings.Replace(quiz.Title, "\n", " ", -1))
+	}
+
+	category := ""
+	for i := range quiz.Problems {
+		problem := &quiz.Problems[i]
+		if len(problem.Answers) == 0 {
+			return errors.Errorf("Invalid problem %d: it has no answers", i+1)
+		}
+		if problem.Category != category {
+			category = problem.Category
+			fmt.Fprintf(output, "$CATEGORY: %s\n\n", category)
+		}
+		answers, err := giftAnswerBlock(problem, quiz.Matcher)
+		if err != nil {
+			return errors.Wrapf(err, "Invalid problem %d", i+1)
+		}
+		fmt.Fprintf(output, "%s {%s", giftQuestion(problem), answers)
+		if problem.Explanation != "" {
+			fmt.Fprintf(output, " ####%s", giftEscape(problem.Explanation))
+		}
+		fmt.Fprint(output, "}\n\n")
+	}
+
+	return errors.Wrap(output.Flush(), "Unable to write GIFT quiz file")
+}
+
+// giftQuestion returns the escaped question of a Problem, in Markdown with its code snippet and
+// its image if the problem has any.
+func giftQuestion(problem *Problem) string {
+	content := problem.Content
+	if !content.Markdown && content.Code == "" && content.Image == "" {
+		return giftEscape(problem.Question)
+	}
+
+	text := problem.Question
+	if content.Code != "" {
+		text += "\n\n```" + content.Language + "\n" + strings.TrimRight(content.Code, "\n") + "\n```"
+	}
+	if content.Image != "" {
+		text += "\n\n![](" + content.Image + ")"
+	}
+	return "[markdown]" + giftEscape(text)
+}
+
+// giftAnswerBlock returns the answers of a Problem in GIFT, without the surrounding braces.
+func giftAnswerBlock(problem *Problem, fallback Matcher) (string, error) {
+	var answers []string
+
+	switch problem.Kind {
+	case MultipleChoice, MultipleAnswer:
+		correct, err := correctChoices(problem, fallback)
+		if err != nil {
+			return "", err
+		}
+		if isTrueFalse(problem) {
+			if correct[0] {
+				return "T", nil
+			}
+			return "F", nil
+		}
+
+		count := 0
+		for _, isCorrect := range correct {
+			if isCorrect {
+				count++
+			}
+		}
+		weight := "%" + strconv.FormatFloat(math.Round(1e7/float64(count))/1e5, 'f', -1, 64) + "%"
+		for i, choice := range problem.Choices {
+			mark := "~"
+			switch {
+			case problem.Kind == MultipleChoice && correct[i]:
+				mark = "="
+			case problem.Kind == MultipleAnswer && correct[i]:
+				mark += weight
+			case problem.Kind == MultipleAnswer:
+				mark += "%-100%"
+			}
+			answers = append(answers, mark+giftEscape(choice))
+		}
+	default:
+		numeric, err := literalMatcher(problem, fallback)
+		if err != nil {
+			return "", err
+		}
+		if numeric != nil {
+			for _, answer := range problem.Answers {
+				answer = strings.TrimSpace(answer)
+				if numeric.Tolerance != 0 {
+					answer += ":" + strconv.FormatFloat(numeric.Tolerance, 'f', -1, 64)
+				}
+				answers = append(answers, answer)
+			}
+			if len(answers) == 1 {
+				return "#" + answers[0], nil
+			}
+			return "#=" + strings.Join(answers, " ="), nil
+		}
+		for _, answer := range problem.Answers {
+			answers = append(answers, "="+giftEscape(answer))
+		}
+	}
+
+	return strings.Join(answers, " "), nil
+}
+
+// correctChoices returns whether each choice of a choice problem is correct, comparing them with
+// the answers using the matcher of the problem, as LMSs mark the correct choices themselves. It
+// fails if no choice is correct or, for MultipleChoice problems, if several are.
+func correctChoices(problem *Problem, fallback Matcher) ([]bool, error) {
+	matcher := problem.matcher(fallback)
+	correct := make([]bool, len(problem.Choices))
+	count := 0
+	for i, choice := range problem.Choices {
+		if problem.isAnswer(choice, matcher) {
+			correct[i] = true
+			count++
+		}
+	}
+
+	switch {
+	case count == 0:
+		return nil, errors.New("none of its choices is a correct answer")
+	case count > 1 && problem.Kind == MultipleChoice:
+		return nil, errors.New("several of its choices are correct answers, but only one can be chosen")
+	}
+	return correct, nil
+}
+
+// literalMatcher checks that the answers of an Open problem can be written for an LMS, which
+// compares them literally, ignoring case, or as numbers. It returns the Numeric matcher of the
+// problem when they are compared as numbers, and nil otherwise.
+func literalMatcher(problem *Problem, fallback Matcher) (*Numeric, error) {
+	switch matcher := problem.matcher(fallback).(type) {
+	case Exact, CaseInsensitive:
+		return nil, nil
+	case Numeric:
+		if !allNumbers(problem.Answers) {
+			return nil, errors.Errorf("the %s matcher needs numeric answers", matcher)
+		}
+		return &matcher, nil
+	default:
+		return nil, errors.Errorf("the %s matcher can't be written for an LMS", matcher)
+	}
+}
+
+// isTrueFalse checks whether a problem is a choice between "True" and "False".
+func isTrueFalse(problem *Problem) bool {
+	return problem.Kind == MultipleChoice && len(problem.Choices) == 2 &&
+		strings.EqualFold(problem.Choices[0], "True") && strings.EqualFold(problem.Choices[1], "False")
+}
+
+// allNumbers checks whether all the given answers are numbers.
+func allNumbers(answers []string) bool {
+	for _, answer := range answers {
+		if _, err := strconv.ParseFloat(strings.TrimSpace(answer), 64); err != nil {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,145 @@
+package model
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const giftQuiz = `// Sample quiz
+$CATEGORY: $course$/Geography/capitals
+
+::France:: Capital of France? {=Paris ~London ~Rome #### Paris is on the Seine}
+
+Which are prime numbers? {
+~%50%2 # Yes
+~%50%3
+~%-100%4
+}
+
+$CATEGORY: arithmetic
+
+What is 5+5? {#10:0.5}
+
+[markdown]The sun is a **star**. {T}
+
+Which city is known as the Big Apple? {=NYC =New York}
+
+Two plus {#1.9..2.1} equals four.
+`
+
+func expectedGIFTQuiz() *Quiz {
+	france := NewProblem("Capital of France?", []string{"Paris"}, []string{"Paris", "London", "Rome"})
+	france.Explanation = "Paris is on the Seine"
+	france.Category = "capitals"
+	primes := NewProblem("Which are prime numbers?", []string{"2", "3"}, []string{"2", "3", "4"})
+	primes.Category = "capitals"
+	sum := NewProblem("What is 5+5?", []string{"10"}, nil)
+	sum.Matcher = Numeric{0.5}
+	sum.Category = "arithmetic"
+	sun := NewProblem("The sun is a **star**.", []string{"True"}, []string{"True", "False"})
+	sun.Content.Markdown = true
+	sun.Category = "arithmetic"
+	city := NewProblem("Which city is known as the Big Apple?", []string{"NYC", "New York"}, nil)
+	city.Matcher = CaseInsensitive{}
+	city.Category = "arithmetic"
+	blank := NewProblem("Two plus _____ equals four.", []string{"2"}, nil)
+	blank.Matcher = Numeric{0.1}
+	blank.Category = "arithmetic"
+
+	return &Quiz{Problems: []Problem{france, primes, sum, sun, city, blank}}
+}
+
+func TestReadGIFT(t *testing.T) {
+	quiz, err := ReadGIFT(strings.NewReader(giftQuiz))
+
+	if err != nil {
+		t.Fatalf("Expected valid result, but an error was returned: %+v", err)
+	}
+	if expected := expectedGIFTQuiz(); !reflect.DeepEqual(quiz, expected) {
+		t.Errorf("Expected quiz %+v, but got %+v", expected, quiz)
+	}
+}
+
+func TestReadGIFTReportsAllMalformedQuestions(t *testing.T) {
+	gift := "Capital of France? {=Paris}\n\nWrite an essay {}\n\n// Comment\nWhat is 5+5? {#ten}\n\nUnclosed {=answer\n"
+
+	_, err := ReadGIFT(strings.NewReader(gift))
+
+	lineErrors, ok := err.(LineErrors)
+	if !ok {
+		t.Fatalf("Expected LineErrors, but got %v", err)
+	}
+	lines := make([]int, len(lineErrors))
+	for i, lineError := range lineErrors {
+		lines[i] = lineError.Line
+	}
+	if expected := []int{3, 6, 8}; !reflect.DeepEqual(lines, expected) {
+		t.Errorf("Expected errors in lines %v, but got %v", expected, lines)
+	}
+}
+
+func TestWriteGIFTRoundTrip(t *testing.T) {
+	expected := expectedGIFTQuiz()
+	expected.Problems[0].Question = "Capital of {France}: #1?"
+	expected.Problems[1].Choices[2] = "4 ~ 2=2"
+	expected.Problems[5].Matcher = Numeric{0.125}
+
+	var buffer bytes.Buffer
+	if err := WriteGIFT(&buffer, expected); err != nil {
+		t.Fatalf("Expected GIFT quiz to be written, but an error was returned: %+v", err)
+	}
+	quiz, err := ReadGIFT(&buffer)
+
+	if err != nil {
+		t.Fatalf("Expected written GIFT quiz to be loaded, but an error was returned: %+v", err)
+	}
+	if !reflect.DeepEqual(quiz, expected) {
+		t.Errorf("Expected quiz %+v, but got %+v", expected, quiz)
+	}
+}
+
+func TestWriteGIFTWritesRichContentInMarkdown(t *testing.T) {
+	problem := NewProblem("What does it print?", []string{"10"}, nil)
+	problem.Content = Content{Code: "fmt.Println(5 + 5)", Language: "go", Image: "gopher.png"}
+
+	var buffer bytes.Buffer
+	if err := WriteGIFT(&buffer, &Quiz{Title: "Go", Problems: []Problem{problem}}); err != nil {
+		t.Fatalf("Expected GIFT quiz to be written, but an error was returned: %+v", err)
+	}
+
+	expected := "// Go\n\n[markdown]What does it print?\\n\\n```go\\nfmt.Println(5 + 5)\\n```\\n\\n![](gopher.png) {=10}\n\n"
+	if buffer.String() != expected {
+		t.Errorf("Expected GIFT %q, but got %q", expected, buffer.String())
+	}
+}
+
+func TestWriteGIFTResolvesChoicesWithTheMatcher(t *testing.T) {
+	capital := NewProblem("Capital of France?", []string{"paris"}, []string{"Paris", "Rome"})
+	capital.Matcher = CaseInsensitive{}
+
+	var buffer bytes.Buffer
+	if err := WriteGIFT(&buffer, &Quiz{Problems: []Problem{capital}}); err != nil {
+		t.Fatalf("Expected GIFT quiz to be written, but an error was returned: %+v", err)
+	}
+	if expected := "Capital of France? {=Paris ~Rome}\n\n"; buffer.String() != expected {
+		t.Errorf("Expected GIFT %q, but got %q", expected, buffer.String())
+	}
+}
+
+func TestWriteGIFTRejectsProblemsWhichCantBeWritten(t *testing.T) {
+	colour := NewProblem("How is colour spelled?", []string{"colou?r"}, nil)
+	colour.Matcher = Regex{}
+	capital := NewProblem("Capital of France?", []string{"Madrid"}, []string{"Paris", "Rome"})
+
+	for _, problem := range []Problem{colour, capital} {
+		quiz := &Quiz{Problems: []Problem{problem}}
+		if err := WriteGIFT(&bytes.Buffer{}, quiz); err == nil {
+			t.Errorf("Expected an error writing %q in GIFT, but got none", problem.Question)
+		}
+		if err := WriteQTI(&bytes.Buffer{}, quiz); err == nil {
+			t.Errorf("Expected an error writing %q in QTI, but got none", problem.Question)
+		}
+	}
+}
@@ -0,0 +1,129 @@
+package model
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Format is a representation of a Quiz which can be loaded from a file.
+type Format struct {
+	// Name of the format, used to choose it explicitly
+	Name string
+	// Extensions of the files in this format, including the dot (e.g. ".csv")
+	Extensions []string
+	// Load reads a Quiz in this format from the given reader
+	Load func(reader io.Reader) (*Quiz, error)
+	// Save writes a Quiz in this format to the given writer, nil if the format is read-only
+	Save func(writer io.Writer, quiz *Quiz) error
+}
+
+// formats holds the registered formats by name.
+var formats = make(map[string]Format)
+
+// RegisterFormat registers a Format so quizzes in that format can be loaded with Load, and
+// saved with Save if the format can be written.
+// Registering a format with the name of an existing one replaces it.
+func RegisterFormat(format Format) {
+	formats[strings.ToLower(format.Name)] = format
+}
+
+// Formats returns the names of the registered formats, sorted alphabetically.
+func Formats() []string {
+	names := make([]string, 0, len(formats))
+	for name := range formats {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// FormatFor returns the Format with the given name or, if the name is empty, the Format
+// registered for the extension of the given path.
+func FormatFor(path, name string) (Format, error) {
+	if name != "" {
+		format, ok := formats[strings.ToLower(name)]
+		if !ok {
+			return Format{}, errors.Errorf("Unknown quiz format %q, use one of %v", name, Formats())
+		}
+		return format, nil
+	}
+
+	extension := strings.ToLower(filepath.Ext(path))
+	for _, format := range formats {
+		for _, formatExtension := range format.Extensions {
+			if extension == formatExtension {
+				return format, nil
+			}
+		}
+	}
+
+	return Format{}, errors.Errorf("Unable to guess the quiz format of %q, use one of %v", path, Formats())
+}
+
+// Load loads a Quiz from the file in the given path, in the format with the given name or,
+// if the name is empty, in the format registered for the extension of the file.
+func Load(path, formatName string) (*Quiz, error) {
+	format, err := FormatFor(path, formatName)
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, errors.Wrap(err, "Unable to open quiz file")
+	}
+	defer file.Close()
+
+	return format.Load(file)
+}
+
+// Save saves the given Quiz to the file in the given path, in the format with the given name
+// or, if the name is empty, in the format registered for the extension of the file.
+func Save(quiz *Quiz, path, formatName string) error {
+	format, err := FormatFor(path, formatName)
+	if err != nil {
+		return err
+	}
+	if format.Save == nil {
+		return errors.Errorf("Unable to write quizzes in %s format", format.Name)
+	}
+
+	file, err := os.Create(path)
+	if err != nil {
+		return errors.Wrap(err, "Unable to create quiz file")
+	}
+	defer file.Close()
+
+	if err := format.Save(file, quiz); err != nil {
+		return err
+	}
+	return errors.Wrap(file.Close(), "Unable to write quiz file")
+}
+
+// ReplaceFile replaces the contents of the file in the given path with the given data. The data
+// is synced to a temporary file which is then renamed over the file, so the previous contents
+// are kept if the write is interrupted, even by a crash.
+func ReplaceFile(path string, data []byte) error {
+	temporary := path + ".tmp"
+	file, err := os.OpenFile(temporary, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0644)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	if _, err := file.Write(data); err != nil {
+		return err
+	}
+	if err := file.Sync(); err != nil {
+		return err
+	}
+	if err := file.Close(); err != nil {
+		return err
+	}
+	return os.Rename(temporary, path)
+}
@@ -0,0 +1,202 @@
+package model
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/roberveral/gophercises/quiz/report"
+	"github.com/roberveral/gophercises/quiz/runner"
+)
+
+// Contestant is a participant of a match, who is asked the questions through its Runner.
+type Contestant struct {
+	// Name of the contestant, like the name of a team
+	Name   string
+	Runner runner.Runner
+}
+
+// buzz is the answer given by a contestant in a round of a match.
+type buzz struct {
+	contestant int
+	answer     string
+	elapsed    time.Duration
+}
+
+// Compete executes the quiz as a head-to-head match between the given contestants, with buzzer
+// semantics: each problem is asked to all of them at once, and only the first correct answer
+// scores. A round ends when a contestant answers correctly, when all of them have answered or
+// when the time to answer the problem expires, so each contestant answers once per round and the
+// pending questions of the others are cancelled. Wrong answers are scored, and penalized, as usual.
+// Rounds without time limit last until somebody answers correctly or everybody answers.
+// The match ends, as if its time expired, when the given context is done.
+// Problems are asked in order, even in adaptive quizzes, and the match can't be paused.
+// It returns the final standings, ranked by points and then by correct answers, with the
+// report of each contestant, which are also shown to the Runners which are runner.StandingsRunner.
+func (q *Quiz) Compete(ctx context.Context, contestants []Contestant) []runner.Standing {
+	reports := make([]*report.Report, len(contestants))
+	for c := range contestants {
+		reports[c] = q.newReport()
+	}
+
+	for i := range q.Problems {
+		problem := &q.Problems[i]
+		entries := q.round(ctx, contestants, i, problem)
+		timedOut := ctx.Err() != nil
+
+		for c, entry := range entries {
+			quizReport := reports[c]
+			if entry.Correct {
+				quizReport.Correct++
+			}
+			quizReport.Points += entry.Points
+			quizReport.Questions = append(quizReport.Questions, entry.Question)
+
+			if timedOut {
+				continue
+			}
+			contestantRunner := contestants[c].Runner
+			if entry.TimedOut {
+				contestantRunner.NotifyQuestionTimeout(i)
+			}
+			if feedbackRunner, ok := contestantRunner.(runner.FeedbackRunner); ok && (!entry.TimedOut || q.Feedback) {
+				feedback := runner.Feedback{Number: i, Correct: entry.Correct, TimedOut: entry.TimedOut, Answer: entry.Answer}
+				if entry.winner >= 0 && entry.winner != c {
+					feedback.Winner = contestants[entry.winner].Name
+				}
+				if q.Feedback {
+					feedback.Expected, feedback.Explanation = problem.Answers, problem.Explanation
+				}
+				feedbackRunner.NotifyAnswer(feedback)
+			}
+		}
+
+		if timedOut {
+			for c, contestant := range contestants {
+				quizReport := reports[c]
+				quizReport.TimedOut = true
+				for _, unanswered := range q.Problems[i+1:] {
+					quizReport.Questions = append(quizReport.Questions, report.Question{
+						Number:    len(quizReport.Questions),
+						Question:  unanswered.Question,
+						Expected:  unanswered.Answers,
+						MaxPoints: unanswered.MaxPoints(),
+						TimedOut:  true,
+					})
+				}
+				contestant.Runner.NotifyTimeout(quizReport)
+			}
+			break
+		}
+	}
+
+	standings := rankContestants(contestants, reports)
+	for c, contestant := range contestants {
+		if !reports[c].TimedOut {
+			contestant.Runner.ShowResults(reports[c])
+		}
+		if standingsRunner, ok := contestant.Runner.(runner.StandingsRunner); ok {
+			standingsRunner.ShowStandings(standings)
+		}
+	}
+	return standings
+}
+
+// roundEntry is the outcome of a round of a match for a contestant, along with the contestant
+// who answered first, or -1 if nobody did.
+type roundEntry struct {
+	report.Question
+	winner int
+}
+
+// round asks the given problem to all the contestants at once, returning the outcome of the
+// round for each of them. It waits for all the pending questions to be cancelled.
+func (q *Quiz) round(ctx context.Context, contestants []Contestant, number int, problem *Problem) []roundEntry {
+	questionTime := startCountdown(q.timeoutFor(problem))
+	defer questionTime.stop()
+	roundCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	question := problem.prompt()
+	question.Total = len(q.Problems)
+	question.QuestionRemaining = questionTime.left()
+
+	// Answers are buffered, so the runners don't block once the round is over
+	answers := make(chan buzz, len(contestants))
+	start := time.Now()
+	for c, contestant := range contestants {
+		go func(c int, contestantRunner runner.Runner) {
+			answer := contestantRunner.Ask(roundCtx, number, question)
+			answers <- buzz{c, answer, time.Since(start)}
+		}(c, contestant.Runner)
+	}
+
+	entries := make([]roundEntry, len(contestants))
+	for c := range entries {
+		entries[c] = roundEntry{report.Question{Number: number, Question: problem.Question, Expected: problem.Answers, MaxPoints: problem.MaxPoints()}, -1}
+	}
+	answered := make([]bool, len(contestants))
+
+	winner, pending, expired := -1, len(contestants), false
+	for pending > 0 && winner < 0 && !expired {
+		select {
+		case answer := <-answers:
+			pending--
+			answered[answer.contestant] = true
+			entry := &entries[answer.contestant]
+			entry.Answer = strings.TrimSpace(answer.answer)
+			entry.Elapsed = answer.elapsed
+			entry.Correct = problem.checkAnswer(answer.answer, q.Matcher)
+			entry.Points = problem.score(answer.answer, q.Matcher, q.Scoring)
+			entry.AnsweredAt = start.Add(answer.elapsed)
+			entry.TooFast = q.tooFast(answer.answer, answer.elapsed)
+			if entry.Correct {
+				winner = answer.contestant
+			}
+		case <-questionTime.expired():
+			expired = true
+		case <-ctx.Done():
+			expired = true
+		}
+	}
+
+	// The answers given after the round was over are dropped, so wait for the runners to give up
+	cancel()
+	for ; pending > 0; pending-- {
+		answer := <-answers
+		if !answered[answer.contestant] {
+			entries[answer.contestant].Elapsed = answer.elapsed
+		}
+	}
+
+	for c := range entries {
+		entries[c].winner = winner
+		entries[c].TimedOut = expired && !answered[c]
+	}
+	return entries
+}
+
+// rankContestants returns the standings of the contestants with the given reports, ranked by
+// points and then by correct answers. Contestants who tie share the rank.
+func rankContestants(contestants []Contestant, reports []*report.Report) []runner.Standing {
+	standings := make([]runner.Standing, len(contestants))
+	for c, contestant := range contestants {
+		standings[c] = runner.Standing{Name: contestant.Name, Correct: reports[c].Correct, Points: reports[c].Points, Report: reports[c]}
+	}
+
+	sort.SliceStable(standings, func(i, j int) bool {
+		if standings[i].Points != standings[j].Points {
+			return standings[i].Points > standings[j].Points
+		}
+		return standings[i].Correct > standings[j].Correct
+	})
+
+	for i := range standings {
+		standings[i].Rank = i + 1
+		if i > 0 && standings[i].Points == standings[i-1].Points && standings[i].Correct == standings[i-1].Correct {
+			standings[i].Rank = standings[i-1].Rank
+		}
+	}
+	return standings
+}
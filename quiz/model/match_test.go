@@ -0,0 +1,90 @@
+package model
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/roberveral/gophercises/quiz/report"
+	"github.com/roberveral/gophercises/quiz/runner"
+)
+
+// buzzerRunner answers each question after the given delay, and never answers (blocking until
+// the question is cancelled) when the delay is negative. It keeps the feedback received.
+type buzzerRunner struct {
+	answers  []string
+	delays   []time.Duration
+	mutex    sync.Mutex
+	feedback []runner.Feedback
+}
+
+func (r *buzzerRunner) Ask(ctx context.Context, number int, question runner.Question) string {
+	if r.delays[number] < 0 {
+		<-ctx.Done()
+		return ""
+	}
+
+	select {
+	case <-time.After(r.delays[number]):
+		return r.answers[number]
+	case <-ctx.Done():
+		return ""
+	}
+}
+
+func (r *buzzerRunner) NotifyAnswer(feedback runner.Feedback) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.feedback = append(r.feedback, feedback)
+}
+
+func (r *buzzerRunner) ShowResults(quizReport *report.Report)   {}
+func (r *buzzerRunner) NotifyTimeout(quizReport *report.Report) {}
+func (r *buzzerRunner) NotifyQuestionTimeout(number int)        {}
+
+func TestCompeteScoresTheFirstCorrectAnswer(t *testing.T) {
+	quiz := testQuiz()
+	quiz.Problems = append(quiz.Problems, NewProblem("3+3", []string{"6"}, nil))
+	quiz.QuestionTimeout = 500 * time.Millisecond
+
+	ms := time.Millisecond
+	alice := &buzzerRunner{answers: []string{"10", "2", "4", ""}, delays: []time.Duration{50 * ms, 200 * ms, -1, -1}}
+	bob := &buzzerRunner{answers: []string{"11", "2", "4", ""}, delays: []time.Duration{0, 0, 10 * ms, -1}}
+
+	standings := quiz.Compete(context.Background(), []Contestant{{"Alice", alice}, {"Bob", bob}})
+
+	if len(standings) != 2 || standings[0].Name != "Bob" || standings[0].Points != 2 || standings[1].Name != "Alice" || standings[1].Points != 1 {
+		t.Fatalf("Expected Bob to win with 2 points over Alice with 1, but got %+v", standings)
+	}
+	if standings[0].Rank != 1 || standings[1].Rank != 2 {
+		t.Errorf("Expected ranks 1 and 2, but got %+v", standings)
+	}
+
+	aliceReport := standings[1].Report
+	if answer := aliceReport.Questions[1]; answer.Answer != "" || answer.Correct || answer.TimedOut {
+		t.Errorf("Expected Alice to be beaten to the second question, but got %+v", answer)
+	}
+	if answer := aliceReport.Questions[3]; !answer.TimedOut {
+		t.Errorf("Expected the last question to time out, but got %+v", answer)
+	}
+	if feedback := alice.feedback[1]; feedback.Winner != "Bob" {
+		t.Errorf("Expected Alice to be told Bob answered first, but got %+v", feedback)
+	}
+	if feedback := bob.feedback[0]; feedback.Correct || feedback.Winner != "Alice" || feedback.Answer != "11" {
+		t.Errorf("Expected Bob's wrong answer to be reported along with the winner, but got %+v", feedback)
+	}
+}
+
+func TestCompeteEndsWhenTheContextIsDone(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	blocked := &buzzerRunner{delays: []time.Duration{-1, -1, -1}}
+
+	standings := testQuiz().Compete(ctx, []Contestant{{"Alice", blocked}})
+
+	quizReport := standings[0].Report
+	if !quizReport.TimedOut || len(quizReport.Questions) != 3 {
+		t.Errorf("Expected the match to time out with all the questions reported, but got %+v", quizReport)
+	}
+}
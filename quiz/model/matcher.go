@@ -0,0 +1,171 @@
+package model
+
+import (
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Matcher decides whether the answer given by the user matches an expected answer.
+// Both answers are received as they are, so implementations have to deal with
+// surrounding whitespace.
+type Matcher interface {
+	// Match checks whether the given answer matches the expected one.
+	Match(expected, answer string) bool
+}
+
+// Exact is a Matcher which accepts answers equal to the expected one, ignoring surrounding whitespace.
+type Exact struct{}
+
+// Match checks whether the given answer matches the expected one.
+func (Exact) Match(expected, answer string) bool {
+	return strings.TrimSpace(expected) == strings.TrimSpace(answer)
+}
+
+// String returns the specification of the matcher, as understood by ParseMatcher.
+func (Exact) String() string { return "exact" }
+
+// CaseInsensitive is a Matcher which accepts answers equal to the expected one under Unicode case folding,
+// so "paris" matches "Paris".
+type CaseInsensitive struct{}
+
+// Match checks whether the given answer matches the expected one.
+func (CaseInsensitive) Match(expected, answer string) bool {
+	return strings.EqualFold(strings.TrimSpace(expected), strings.TrimSpace(answer))
+}
+
+// String returns the specification of the matcher, as understood by ParseMatcher.
+func (CaseInsensitive) String() string { return "fold" }
+
+// Numeric is a Matcher which parses both answers as numbers and accepts the answer
+// when it is within the given tolerance of the expected one, so "10.0" matches "10".
+type Numeric struct {
+	// Tolerance is the maximum absolute difference accepted
+	Tolerance float64
+}
+
+// Match checks whether the given answer matches the expected one.
+func (m Numeric) Match(expected, answer string) bool {
+	want, err := strconv.ParseFloat(strings.TrimSpace(expected), 64)
+	if err != nil {
+		return false
+	}
+	got, err := strconv.ParseFloat(strings.TrimSpace(answer), 64)
+	if err != nil {
+		return false
+	}
+	return math.Abs(want-got) <= m.Tolerance
+}
+
+// String returns the specification of the matcher, as understood by ParseMatcher.
+func (m Numeric) String() string {
+	if m.Tolerance == 0 {
+		return "numeric"
+	}
+	return "numeric:" + strconv.FormatFloat(m.Tolerance, 'g', -1, 64)
+}
+
+// Regex is a Matcher which treats the expected answer as a regular expression that has
+// to match the whole answer. Invalid expressions never match.
+type Regex struct{}
+
+// Match checks whether the given answer matches the expected one.
+func (Regex) Match(expected, answer string) bool {
+	re, err := regexp.Compile("^(?:" + strings.TrimSpace(expected) + ")$")
+	if err != nil {
+		return false
+	}
+	return re.MatchString(strings.TrimSpace(answer))
+}
+
+// String returns the specification of the matcher, as understood by ParseMatcher.
+func (Regex) String() string { return "regex" }
+
+// Fuzzy is a Matcher which accepts answers whose Levenshtein distance to the expected one,
+// ignoring case, is at most MaxDistance. This allows small typos like "Pariss".
+type Fuzzy struct {
+	// MaxDistance is the maximum number of single character edits accepted
+	MaxDistance int
+}
+
+// Match checks whether the given answer matches the expected one.
+func (m Fuzzy) Match(expected, answer string) bool {
+	expected = strings.ToLower(strings.TrimSpace(expected))
+	answer = strings.ToLower(strings.TrimSpace(answer))
+	return levenshtein([]rune(expected), []rune(answer)) <= m.MaxDistance
+}
+
+// String returns the specification of the matcher, as understood by ParseMatcher.
+func (m Fuzzy) String() string { return "fuzzy:" + strconv.Itoa(m.MaxDistance) }
+
+// ParseMatcher creates a Matcher from its textual specification, which is the name of the
+// matcher optionally followed by a parameter: "exact", "fold", "numeric[:tolerance]", "regex"
+// or "fuzzy[:distance]". Fuzzy matching allows a distance of 1 unless told otherwise.
+func ParseMatcher(spec string) (Matcher, error) {
+	name, param := strings.TrimSpace(spec), ""
+	if i := strings.Index(name, ":"); i >= 0 {
+		name, param = name[:i], name[i+1:]
+	}
+
+	switch strings.ToLower(name) {
+	case "", "exact":
+		return Exact{}, nil
+	case "fold":
+		return CaseInsensitive{}, nil
+	case "regex":
+		return Regex{}, nil
+	case "numeric":
+		if param == "" {
+			return Numeric{}, nil
+		}
+		tolerance, err := strconv.ParseFloat(param, 64)
+		if err != nil || tolerance < 0 {
+			return nil, errors.Errorf("Invalid numeric tolerance %q", param)
+		}
+		return Numeric{tolerance}, nil
+	case "fuzzy":
+		if param == "" {
+			return Fuzzy{1}, nil
+		}
+		distance, err := strconv.Atoi(param)
+		if err != nil || distance < 0 {
+			return nil, errors.Errorf("Invalid fuzzy distance %q", param)
+		}
+		return Fuzzy{distance}, nil
+	default:
+		return nil, errors.Errorf("Unknown answer matcher %q", spec)
+	}
+}
+
+// levenshtein computes the minimum number of single character edits to turn a into b.
+func levenshtein(a, b []rune) int {
+	previous := make([]int, len(b)+1)
+	current := make([]int, len(b)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+
+	for i := 1; i <= len(a); i++ {
+		current[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			current[j] = minInt(previous[j]+1, minInt(current[j-1]+1, previous[j-1]+cost))
+		}
+		previous, current = current, previous
+	}
+
+	return previous[len(b)]
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
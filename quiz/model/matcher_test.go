@@ -0,0 +1,53 @@
+package model
+
+import "testing"
+
+func matchTest(t *testing.T, matcher Matcher, expected, answer string, result bool) {
+	t.Helper()
+	if matcher.Match(expected, answer) != result {
+		t.Errorf("Expected %v.Match(%q, %q) to be %v", matcher, expected, answer, result)
+	}
+}
+
+func TestBuiltinMatchers(t *testing.T) {
+	matchTest(t, Exact{}, "Paris", " Paris\n", true)
+	matchTest(t, Exact{}, "Paris", "paris", false)
+	matchTest(t, CaseInsensitive{}, "Paris", "paris", true)
+	matchTest(t, Numeric{}, "10", "10.0", true)
+	matchTest(t, Numeric{0.01}, "3.14", "3.141", true)
+	matchTest(t, Numeric{0.01}, "3.14", "3.2", false)
+	matchTest(t, Numeric{}, "10", "ten", false)
+	matchTest(t, Regex{}, "colou?r", "color", true)
+	matchTest(t, Regex{}, "colou?r", "colors", false)
+	matchTest(t, Fuzzy{1}, "Paris", "pariss", true)
+	matchTest(t, Fuzzy{1}, "Paris", "Parsi", false)
+	matchTest(t, Fuzzy{2}, "Paris", "Parsi", true)
+}
+
+func TestParseMatcher(t *testing.T) {
+	cases := map[string]Matcher{
+		"":            Exact{},
+		"exact":       Exact{},
+		"fold":        CaseInsensitive{},
+		"numeric":     Numeric{},
+		"numeric:0.5": Numeric{0.5},
+		"regex":       Regex{},
+		"fuzzy":       Fuzzy{1},
+		"fuzzy:3":     Fuzzy{3},
+	}
+
+	for spec, expected := range cases {
+		matcher, err := ParseMatcher(spec)
+		if err != nil {
+			t.Errorf("Expected valid matcher for %q, but an error was returned: %+v", spec, err)
+		} else if matcher != expected {
+			t.Errorf("Expected matcher %v for %q, but got %v", expected, spec, matcher)
+		}
+	}
+
+	for _, spec := range []string{"unknown", "numeric:x", "fuzzy:-1"} {
+		if _, err := ParseMatcher(spec); err == nil {
+			t.Errorf("Expected an error for matcher %q", spec)
+		}
+	}
+}
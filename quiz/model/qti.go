@@ -0,0 +1,468 @@
+package model
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+func init() {
+	RegisterFormat(Format{Name: "qti", Extensions: []string{".xml", ".qti"}, Load: ReadQTI, Save: WriteQTI})
+}
+
+// qtiDocument is the representation of a Quiz as an IMS QTI 1.2 assessment, as imported and
+// exported by LMSs like Moodle, Canvas or Blackboard.
+type qtiDocument struct {
+	XMLName    xml.Name      `xml:"questestinterop"`
+	Assessment qtiAssessment `xml:"assessment"`
+}
+
+type qtiAssessment struct {
+	Ident    string       `xml:"ident,attr"`
+	Title    string       `xml:"title,attr"`
+	Sections []qtiSection `xml:"section"`
+}
+
+// qtiSection groups the items of a category.
+type qtiSection struct {
+	Ident string    `xml:"ident,attr"`
+	Title string    `xml:"title,attr,omitempty"`
+	Items []qtiItem `xml:"item"`
+}
+
+type qtiItem struct {
+	Ident      string          `xml:"ident,attr"`
+	Title      string          `xml:"title,attr,omitempty"`
+	Metadata   []qtiField      `xml:"itemmetadata>qtimetadata>qtimetadatafield"`
+	Material   qtiMaterial     `xml:"presentation>material"`
+	Choice     *qtiResponseLid `xml:"presentation>response_lid"`
+	Text       *qtiResponseStr `xml:"presentation>response_str"`
+	Outcomes   []qtiDecVar     `xml:"resprocessing>outcomes>decvar"`
+	Conditions []qtiCondition  `xml:"resprocessing>respcondition"`
+	Feedback   []qtiFeedback   `xml:"itemfeedback"`
+}
+
+type qtiField struct {
+	Label string `xml:"fieldlabel"`
+	Entry string `xml:"fieldentry"`
+}
+
+type qtiMaterial struct {
+	Texts []qtiText `xml:"mattext"`
+	Image *qtiImage `xml:"matimage"`
+}
+
+type qtiText struct {
+	Type  string `xml:"texttype,attr,omitempty"`
+	Value string `xml:",chardata"`
+}
+
+type qtiImage struct {
+	URI string `xml:"uri,attr"`
+}
+
+type qtiResponseLid struct {
+	Ident       string     `xml:"ident,attr"`
+	Cardinality string     `xml:"rcardinality,attr"`
+	Labels      []qtiLabel `xml:"render_choice>response_label"`
+}
+
+type qtiLabel struct {
+	Ident    string      `xml:"ident,attr"`
+	Material qtiMaterial `xml:"material"`
+}
+
+type qtiResponseStr struct {
+	Ident       string     `xml:"ident,attr"`
+	Cardinality string     `xml:"rcardinality,attr"`
+	Labels      []qtiLabel `xml:"render_fib>response_label"`
+}
+
+type qtiDecVar struct {
+	VarName string `xml:"varname,attr"`
+	Type    string `xml:"vartype,attr"`
+	Min     string `xml:"minvalue,attr,omitempty"`
+	Max     string `xml:"maxvalue,attr,omitempty"`
+}
+
+type qtiCondition struct {
+	Continue string          `xml:"continue,attr,omitempty"`
+	Var      qtiConditionVar `xml:"conditionvar"`
+	Score    []qtiSetVar     `xml:"setvar"`
+}
+
+// qtiConditionVar is the condition checked on the response to an item. Only equality, the numeric
+// ranges and their combinations are understood.
+type qtiConditionVar struct {
+	Equal []qtiValue        `xml:"varequal"`
+	GTE   []qtiValue        `xml:"vargte"`
+	LTE   []qtiValue        `xml:"varlte"`
+	And   []qtiConditionVar `xml:"and"`
+	Or    []qtiConditionVar `xml:"or"`
+	Not   []qtiConditionVar `xml:"not"`
+}
+
+type qtiValue struct {
+	RespIdent string `xml:"respident,attr"`
+	Value     string `xml:",chardata"`
+}
+
+type qtiSetVar struct {
+	Action  string `xml:"action,attr,omitempty"`
+	VarName string `xml:"varname,attr,omitempty"`
+	Value   string `xml:",chardata"`
+}
+
+type qtiFeedback struct {
+	Ident    string      `xml:"ident,attr"`
+	Material qtiMaterial `xml:"flow_mat>material"`
+}
+
+// QTI question types, as named in the metadata of the items by Canvas.
+const (
+	qtiMultipleChoice  = "multiple_choice_question"
+	qtiMultipleAnswers = "multiple_answers_question"
+	qtiTrueFalse       = "true_false_question"
+	qtiShortAnswer     = "short_answer_question"
+	qtiNumerical       = "numerical_question"
+)
+
+// ReadQTI reads a Quiz from an IMS QTI 1.2 assessment. Each item is a problem, whose kind is
+// given by its response: choice items with single cardinality are MultipleChoice, with multiple
+// cardinality MultipleAnswer, and fill in the blank items are Open. The correct answers are the
+// responses which score in the response processing, numeric ranges are compared with a
+// Numeric matcher and short answers ignoring case. The title of the section is the category of its items, the "points_possible"
+// metadata their points and their general feedback their explanation.
+func ReadQTI(reader io.Reader) (*Quiz, error) {
+	var document qtiDocument
+	if err := xml.NewDecoder(reader).Decode(&document); err != nil {
+		return nil, errors.Wrap(err, "Malformed QTI quiz file")
+	}
+
+	quiz := &Quiz{Title: document.Assessment.Title}
+	for _, section := range document.Assessment.Sections {
+		for _, item := range section.Items {
+			problem, err := item.problem()
+			if err != nil {
+				return nil, errors.Wrapf(err, "Invalid item %q", item.Ident)
+			}
+			if section.Title != "" && section.Ident != "root_section" {
+				problem.Category = section.Title
+			}
+			quiz.Problems = append(quiz.Problems, problem)
+		}
+	}
+
+	return quiz, nil
+}
+
+// problem builds the Problem described by a QTI item.
+func (item *qtiItem) problem() (Problem, error) {
+	if len(item.Material.Texts) == 0 || strings.TrimSpace(item.Material.Texts[0].Value) == "" {
+		return Problem{}, errors.New("Missing question")
+	}
+
+	var conditions qtiConditionVar
+	for _, condition := range item.Conditions {
+		if condition.scores() {
+			conditions.Or = append(conditions.Or, condition.Var)
+		}
+	}
+	values, ranges := conditions.accepted()
+
+	var problem Problem
+	switch {
+	case item.Choice != nil:
+		labels := make(map[string]string)
+		var choices, answers []string
+		for _, label := range item.Choice.Labels {
+			text := strings.TrimSpace(label.Material.text())
+			labels[label.Ident] = text
+			choices = append(choices, text)
+		}
+		for _, value := range values {
+			if text, ok := labels[value]; ok {
+				answers = append(answers, text)
+			}
+		}
+		if len(answers) == 0 {
+			return Problem{}, errors.New("Missing correct answer")
+		}
+		problem = NewProblem("", answers, choices)
+		if item.Choice.Cardinality == "Multiple" {
+			problem.Kind = MultipleAnswer
+		} else if len(answers) > 1 {
+			return Problem{}, errors.New("Expected a single correct choice")
+		}
+	case item.Text != nil:
+		// A range accepts the value in the middle of it, with the tolerance to reach its ends
+		tolerance := 0.0
+		for _, accepted := range ranges {
+			tolerance = math.Max(tolerance, (accepted.high-accepted.low)/2)
+		}
+		if len(values) == 0 {
+			for _, accepted := range ranges {
+				values = append(values, strconv.FormatFloat(roundFloat((accepted.low+accepted.high)/2), 'f', -1, 64))
+			}
+		}
+		if len(values) == 0 {
+			return Problem{}, errors.New("Missing correct answer")
+		}
+		problem = NewProblem("", distinct(values), nil)
+		// Short answers are compared ignoring case, as LMSs do
+		problem.Matcher = CaseInsensitive{}
+		if len(ranges) > 0 || item.metadata("question_type") == qtiNumerical {
+			problem.Matcher = Numeric{roundFloat(tolerance)}
+		}
+	default:
+		return Problem{}, errors.New("Unsupported response, expected a choice or a text")
+	}
+
+	problem.Question = strings.TrimSpace(item.Material.Texts[0].Value)
+	problem.Content.Markdown = item.Material.Texts[0].Type == "text/markdown"
+	if len(item.Material.Texts) > 1 {
+		problem.Content.Code = item.Material.Texts[1].Value
+		problem.Content.Language = strings.TrimPrefix(item.Material.Texts[1].Type, "text/x-")
+		if problem.Content.Language == item.Material.Texts[1].Type {
+			problem.Content.Language = ""
+		}
+	}
+	if item.Material.Image != nil {
+		problem.Content.Image = item.Material.Image.URI
+	}
+	for _, feedback := range item.Feedback {
+		if feedback.Ident == "general_fb" {
+			problem.Explanation = strings.TrimSpace(feedback.Material.text())
+		}
+	}
+	if points := item.metadata("points_possible"); points != "" {
+		var err error
+		if problem.Points, err = parsePoints(points); err != nil {
+			return Problem{}, err
+		}
+	}
+
+	return problem, nil
+}
+
+// metadata returns the value of the given metadata field of an item, empty if it has none.
+func (item *qtiItem) metadata(label string) string {
+	for _, field := range item.Metadata {
+		if field.Label == label {
+			return strings.TrimSpace(field.Entry)
+		}
+	}
+	return ""
+}
+
+// scores checks whether a response condition awards points.
+func (condition *qtiCondition) scores() bool {
+	for _, score := range condition.Score {
+		value, err := strconv.ParseFloat(strings.TrimSpace(score.Value), 64)
+		if err == nil && value > 0 && score.Action != "Subtract" {
+			return true
+		}
+	}
+	return false
+}
+
+// accepted returns the values which satisfy the condition and its numeric ranges.
+func (condition *qtiConditionVar) accepted() ([]string, []qtiRange) {
+	var values []string
+	var ranges []qtiRange
+
+	for _, value := range condition.Equal {
+		values = append(values, strings.TrimSpace(value.Value))
+	}
+	if len(condition.GTE) == 1 && len(condition.LTE) == 1 {
+		low, lowErr := strconv.ParseFloat(strings.TrimSpace(condition.GTE[0].Value), 64)
+		high, highErr := strconv.ParseFloat(strings.TrimSpace(condition.LTE[0].Value), 64)
+		if lowErr == nil && highErr == nil && low <= high {
+			ranges = append(ranges, qtiRange{low, high})
+		}
+	}
+
+	children := append(append([]qtiConditionVar(nil), condition.And...), condition.Or...)
+	for _, child := range children {
+		childValues, childRanges := child.accepted()
+		values = append(values, childValues...)
+		ranges = append(ranges, childRanges...)
+	}
+
+	return distinct(values), ranges
+}
+
+// qtiRange is a numeric range accepted as answer, with its lowest and highest values.
+type qtiRange struct {
+	low, high float64
+}
+
+// text returns the text of a material, joining its texts.
+func (material *qtiMaterial) text() string {
+	texts := make([]string, len(material.Texts))
+	for i, text := range material.Texts {
+		texts[i] = text.Value
+	}
+	return strings.Join(texts, "\n")
+}
+
+// distinct returns the given values without repetitions, in their original order.
+func distinct(values []string) []string {
+	seen := make(map[string]bool)
+	var unique []string
+	for _, value := range values {
+		if !seen[value] {
+			seen[value] = true
+			unique = append(unique, value)
+		}
+	}
+	return unique
+}
+
+// WriteQTI writes a Quiz as an IMS QTI 1.2 assessment (see ReadQTI), with a section for each
+// category of consecutive problems. Numeric problems with a tolerance accept the range around
+// their answers, and choice problems between "True" and "False" are true-false questions. Code
+// snippets are written as a second text of the question, typed after their language. The settings
+// of the quiz other than its title, and the time to answer, the tags and the difficulty of the
+// problems, can't be written in QTI, nor the problems which can't be written in GIFT either
+// because of their matcher or their correct choices (see WriteGIFT).
+func WriteQTI(writer io.Writer, quiz *Quiz) error {
+	document := qtiDocument{Assessment: qtiAssessment{Ident: "quiz", Title: quiz.Title}}
+
+	var section *qtiSection
+	for i := range quiz.Problems {
+		problem := &quiz.Problems[i]
+		if len(problem.Answers) == 0 {
+			return errors.Errorf("Invalid problem %d: it has no answers", i+1)
+		}
+		if section == nil || section.Title != problem.Category {
+			ident := "root_section"
+			if len(document.Assessment.Sections) > 0 || problem.Category != "" {
+				ident = fmt.Sprintf("section%d", len(document.Assessment.Sections)+1)
+			}
+			document.Assessment.Sections = append(document.Assessment.Sections, qtiSection{Ident: ident, Title: problem.Category})
+			section = &document.Assessment.Sections[len(document.Assessment.Sections)-1]
+		}
+		item, err := newQTIItem(fmt.Sprintf("q%d", i+1), problem, quiz.Matcher)
+		if err != nil {
+			return errors.Wrapf(err, "Invalid problem %d", i+1)
+		}
+		section.Items = append(section.Items, item)
+	}
+
+	if _, err := io.WriteString(writer, xml.Header); err != nil {
+		return errors.Wrap(err, "Unable to write QTI quiz file")
+	}
+	encoder := xml.NewEncoder(writer)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(document); err != nil {
+		return errors.Wrap(err, "Unable to write QTI quiz file")
+	}
+	_, err := io.WriteString(writer, "\n")
+	return errors.Wrap(err, "Unable to write QTI quiz file")
+}
+
+// newQTIItem builds the QTI item with the given identifier for a Problem.
+func newQTIItem(ident string, problem *Problem, fallback Matcher) (qtiItem, error) {
+	item := qtiItem{
+		Ident:    ident,
+		Material: qtiMaterial{Texts: []qtiText{{Type: "text/plain", Value: problem.Question}}},
+		Outcomes: []qtiDecVar{{VarName: "SCORE", Type: "Decimal", Min: "0", Max: "100"}},
+	}
+	if problem.Content.Markdown {
+		item.Material.Texts[0].Type = "text/markdown"
+	}
+	if problem.Content.Code != "" {
+		codeType := "text/plain"
+		if problem.Content.Language != "" {
+			codeType = "text/x-" + problem.Content.Language
+		}
+		item.Material.Texts = append(item.Material.Texts, qtiText{Type: codeType, Value: problem.Content.Code})
+	}
+	if problem.Content.Image != "" {
+		item.Material.Image = &qtiImage{URI: problem.Content.Image}
+	}
+
+	response := "response1"
+	correct := qtiConditionVar{}
+	questionType := qtiShortAnswer
+
+	switch problem.Kind {
+	case MultipleChoice, MultipleAnswer:
+		item.Choice = &qtiResponseLid{Ident: response, Cardinality: "Single"}
+		questionType = qtiMultipleChoice
+		if problem.Kind == MultipleAnswer {
+			item.Choice.Cardinality = "Multiple"
+			questionType = qtiMultipleAnswers
+		} else if isTrueFalse(problem) {
+			questionType = qtiTrueFalse
+		}
+
+		correctChoice, err := correctChoices(problem, fallback)
+		if err != nil {
+			return qtiItem{}, err
+		}
+		var wrong []qtiValue
+		for j, choice := range problem.Choices {
+			label := fmt.Sprintf("%s_c%d", ident, j+1)
+			item.Choice.Labels = append(item.Choice.Labels, qtiLabel{Ident: label, Material: qtiMaterial{Texts: []qtiText{{Type: "text/plain", Value: choice}}}})
+			if correctChoice[j] {
+				correct.Equal = append(correct.Equal, qtiValue{RespIdent: response, Value: label})
+			} else {
+				wrong = append(wrong, qtiValue{RespIdent: response, Value: label})
+			}
+		}
+		// All the correct choices, and none of the wrong ones, have to be selected
+		if problem.Kind == MultipleAnswer {
+			if len(wrong) > 0 {
+				correct.Not = []qtiConditionVar{{Or: []qtiConditionVar{{Equal: wrong}}}}
+			}
+			correct = qtiConditionVar{And: []qtiConditionVar{correct}}
+		}
+	default:
+		item.Text = &qtiResponseStr{Ident: response, Cardinality: "Single", Labels: []qtiLabel{{Ident: "answer1"}}}
+		// Any of the answers is accepted, or the range around it for numeric answers
+		var accepted qtiConditionVar
+		numeric, err := literalMatcher(problem, fallback)
+		if err != nil {
+			return qtiItem{}, err
+		}
+		if numeric != nil {
+			questionType = qtiNumerical
+		}
+		for _, answer := range problem.Answers {
+			accepted.Equal = append(accepted.Equal, qtiValue{RespIdent: response, Value: strings.TrimSpace(answer)})
+			if numeric != nil && numeric.Tolerance != 0 {
+				value, _ := strconv.ParseFloat(strings.TrimSpace(answer), 64)
+				accepted.And = append(accepted.And, qtiConditionVar{
+					GTE: []qtiValue{{RespIdent: response, Value: strconv.FormatFloat(value-numeric.Tolerance, 'f', -1, 64)}},
+					LTE: []qtiValue{{RespIdent: response, Value: strconv.FormatFloat(value+numeric.Tolerance, 'f', -1, 64)}},
+				})
+			}
+		}
+		correct = accepted
+		if len(accepted.Equal)+len(accepted.And) > 1 {
+			correct = qtiConditionVar{Or: []qtiConditionVar{accepted}}
+		}
+	}
+
+	item.Metadata = []qtiField{
+		{Label: "question_type", Entry: questionType},
+		{Label: "points_possible", Entry: strconv.FormatFloat(problem.MaxPoints(), 'f', -1, 64)},
+	}
+	item.Conditions = []qtiCondition{{
+		Continue: "No",
+		Var:      correct,
+		Score:    []qtiSetVar{{Action: "Set", VarName: "SCORE", Value: "100"}},
+	}}
+	if problem.Explanation != "" {
+		item.Feedback = []qtiFeedback{{Ident: "general_fb", Material: qtiMaterial{Texts: []qtiText{{Type: "text/plain", Value: problem.Explanation}}}}}
+	}
+
+	return item, nil
+}
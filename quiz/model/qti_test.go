@@ -0,0 +1,139 @@
+package model
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const qtiQuiz = `<?xml version="1.0" encoding="UTF-8"?>
+<questestinterop xmlns="http://www.imsglobal.org/xsd/ims_qtiasiv1p2">
+  <assessment ident="a1" title="Capitals">
+    <section ident="root_section">
+      <item ident="i1" title="France">
+        <itemmetadata>
+          <qtimetadata>
+            <qtimetadatafield><fieldlabel>question_type</fieldlabel><fieldentry>multiple_choice_question</fieldentry></qtimetadatafield>
+            <qtimetadatafield><fieldlabel>points_possible</fieldlabel><fieldentry>2</fieldentry></qtimetadatafield>
+          </qtimetadata>
+        </itemmetadata>
+        <presentation>
+          <material><mattext texttype="text/html">Capital of France?</mattext></material>
+          <response_lid ident="response1" rcardinality="Single">
+            <render_choice>
+              <response_label ident="1"><material><mattext>London</mattext></material></response_label>
+              <response_label ident="2"><material><mattext>Paris</mattext></material></response_label>
+            </render_choice>
+          </response_lid>
+        </presentation>
+        <resprocessing>
+          <outcomes><decvar maxvalue="100" minvalue="0" varname="SCORE" vartype="Decimal"/></outcomes>
+          <respcondition continue="Yes">
+            <conditionvar><varequal respident="response1">1</varequal></conditionvar>
+            <displayfeedback feedbacktype="Response" linkrefid="1_fb"/>
+          </respcondition>
+          <respcondition continue="No">
+            <conditionvar><varequal respident="response1">2</varequal></conditionvar>
+            <setvar action="Set" varname="SCORE">100</setvar>
+          </respcondition>
+        </resprocessing>
+        <itemfeedback ident="general_fb">
+          <flow_mat><material><mattext>It is Paris</mattext></material></flow_mat>
+        </itemfeedback>
+      </item>
+    </section>
+    <section ident="s2" title="arithmetic">
+      <item ident="i2" title="Sum">
+        <itemmetadata>
+          <qtimetadata>
+            <qtimetadatafield><fieldlabel>question_type</fieldlabel><fieldentry>numerical_question</fieldentry></qtimetadatafield>
+          </qtimetadata>
+        </itemmetadata>
+        <presentation>
+          <material><mattext>What is 5+5?</mattext></material>
+          <response_str ident="response1" rcardinality="Single">
+            <render_fib fibtype="Decimal"><response_label ident="answer1"/></render_fib>
+          </response_str>
+        </presentation>
+        <resprocessing>
+          <respcondition continue="No">
+            <conditionvar>
+              <vargte respident="response1">9.5</vargte>
+              <varlte respident="response1">10.5</varlte>
+            </conditionvar>
+            <setvar action="Set" varname="SCORE">100</setvar>
+          </respcondition>
+        </resprocessing>
+      </item>
+      <item ident="i3" title="Italy">
+        <presentation>
+          <material><mattext>Capital of Italy?</mattext></material>
+          <response_str ident="response1" rcardinality="Single">
+            <render_fib><response_label ident="answer1"/></render_fib>
+          </response_str>
+        </presentation>
+        <resprocessing>
+          <respcondition continue="No">
+            <conditionvar><varequal respident="response1">Rome</varequal></conditionvar>
+            <setvar action="Set" varname="SCORE">100</setvar>
+          </respcondition>
+        </resprocessing>
+      </item>
+    </section>
+  </assessment>
+</questestinterop>
+`
+
+func TestReadQTI(t *testing.T) {
+	france := NewProblem("Capital of France?", []string{"Paris"}, []string{"London", "Paris"})
+	france.Points = 2
+	france.Explanation = "It is Paris"
+	sum := NewProblem("What is 5+5?", []string{"10"}, nil)
+	sum.Matcher = Numeric{0.5}
+	sum.Category = "arithmetic"
+	italy := NewProblem("Capital of Italy?", []string{"Rome"}, nil)
+	italy.Matcher = CaseInsensitive{}
+	italy.Category = "arithmetic"
+	expected := &Quiz{Title: "Capitals", Problems: []Problem{france, sum, italy}}
+
+	quiz, err := ReadQTI(strings.NewReader(qtiQuiz))
+
+	if err != nil {
+		t.Fatalf("Expected valid result, but an error was returned: %+v", err)
+	}
+	if !reflect.DeepEqual(quiz, expected) {
+		t.Errorf("Expected quiz %+v, but got %+v", expected, quiz)
+	}
+}
+
+func TestReadQTIRejectsItemsWithoutCorrectAnswer(t *testing.T) {
+	qti := strings.Replace(qtiQuiz, ">100<", ">0<", -1)
+
+	if _, err := ReadQTI(strings.NewReader(qti)); err == nil {
+		t.Error("Expected an error for items without correct answer, but got none")
+	}
+}
+
+func TestWriteQTIRoundTrip(t *testing.T) {
+	expected := expectedGIFTQuiz()
+	expected.Title = "Sample <quiz>"
+	expected.Problems[2].Content = Content{Markdown: true, Code: "fmt.Println(5 + 5)", Language: "go", Image: "gopher.png"}
+	expected.Problems[4].Category = ""
+	for i := range expected.Problems {
+		expected.Problems[i].Points = float64(i + 1)
+	}
+
+	var buffer bytes.Buffer
+	if err := WriteQTI(&buffer, expected); err != nil {
+		t.Fatalf("Expected QTI quiz to be written, but an error was returned: %+v", err)
+	}
+	quiz, err := ReadQTI(&buffer)
+
+	if err != nil {
+		t.Fatalf("Expected written QTI quiz to be loaded, but an error was returned: %+v", err)
+	}
+	if !reflect.DeepEqual(quiz, expected) {
+		t.Errorf("Expected quiz %+v, but got %+v", expected, quiz)
+	}
+}
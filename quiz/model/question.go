@@ -1,16 +1,195 @@
 package model
 
-import "strings"
+import (
+	"strconv"
+	"strings"
+	"time"
 
-// Problem is a question to ask and the correct answer to the question.
+	"github.com/pkg/errors"
+
+	"github.com/roberveral/gophercises/quiz/runner"
+)
+
+// Kind is the type of a Problem, which determines how the question is presented
+// to the user and how the answer is checked.
+type Kind int
+
+const (
+	// Open is a Problem answered with free text. Any of the accepted answers is correct.
+	Open Kind = iota
+	// MultipleChoice is a Problem answered by picking one of its choices.
+	MultipleChoice
+	// MultipleAnswer is a Problem answered by picking all of its correct choices.
+	MultipleAnswer
+)
+
+// ParseKind parses the name of a Kind: "open", "choice" or "multi".
+func ParseKind(name string) (Kind, error) {
+	for kind, kindName := range kindNames {
+		if strings.EqualFold(strings.TrimSpace(name), kindName) {
+			return Kind(kind), nil
+		}
+	}
+	return Open, errors.Errorf("Unknown problem kind %q", name)
+}
+
+var kindNames = []string{"open", "choice", "multi"}
+
+// String returns the name of the Kind, as understood by ParseKind.
+func (k Kind) String() string {
+	if int(k) < len(kindNames) {
+		return kindNames[k]
+	}
+	return "Kind(" + strconv.Itoa(int(k)) + ")"
+}
+
+// Problem is a question to ask and the correct answers to the question.
 type Problem struct {
+	// Kind of problem
+	Kind Kind
 	// Question asked
 	Question string
-	// Answer to the question
-	Answer string
+	// Choices offered to the user, only used by MultipleChoice and MultipleAnswer problems
+	Choices []string
+	// Answers accepted as correct. For choice problems they are the correct choices.
+	Answers []string
+	// Matcher used to compare the answers. When nil the Matcher of the Quiz is used.
+	Matcher Matcher
+	// Timeout is the time the user has to answer the question. When zero the QuestionTimeout
+	// of the Quiz is used.
+	Timeout time.Duration
+	// Points awarded for answering the question correctly. When zero the problem is worth 1 point.
+	Points float64
+	// Explanation of the answer, if any
+	Explanation string
+	// Category of the problem, if any
+	Category string
+	// Tags to classify the problem
+	Tags []string
+	// Difficulty of the problem as an Elo rating, where harder problems have higher ratings.
+	// When zero the problem is rated DefaultRating.
+	Difficulty float64
+	// Content shown along with the question, if any
+	Content Content
+}
+
+// Content is the rich content of a question. The runners which can't render it, like the ones
+// for the console, show a text fallback.
+type Content struct {
+	// Markdown indicates that the question is written in Markdown
+	Markdown bool
+	// Code snippet shown along with the question
+	Code string
+	// Language of the code snippet, used to highlight its syntax, e.g. "go"
+	Language string
+	// Image is the path or the URL of an image shown along with the question
+	Image string
+}
+
+// NewProblem creates a Problem for the given question, accepted answers and choices,
+// inferring its Kind: without choices the Problem is Open, with choices and a single
+// answer it is MultipleChoice and with choices and several answers it is MultipleAnswer.
+func NewProblem(question string, answers []string, choices []string) Problem {
+	kind := Open
+	if len(choices) > 0 {
+		kind = MultipleChoice
+		if len(answers) > 1 {
+			kind = MultipleAnswer
+		}
+	}
+
+	return Problem{Kind: kind, Question: question, Choices: choices, Answers: answers}
 }
 
 // CheckAnswer checks whether the given answer is correct for this Problem.
+// For choice problems the answer can be either the number of the choice or its text,
+// and MultipleAnswer problems expect all the correct choices separated by commas.
+// Answers are compared with the Matcher of the Problem, or exactly if it has none.
 func (q *Problem) CheckAnswer(answer string) bool {
-	return strings.TrimSpace(q.Answer) == strings.TrimSpace(answer)
+	return q.checkAnswer(answer, nil)
+}
+
+// checkAnswer checks whether the given answer is correct, comparing with the given
+// Matcher when the Problem doesn't define its own.
+func (q *Problem) checkAnswer(answer string, fallback Matcher) bool {
+	matcher := q.matcher(fallback)
+
+	switch q.Kind {
+	case MultipleChoice:
+		return q.isAnswer(q.choice(answer), matcher)
+	case MultipleAnswer:
+		selected := q.selection(answer)
+		if len(selected) != len(q.Answers) {
+			return false
+		}
+		for _, choice := range selected {
+			if !q.isAnswer(choice, matcher) {
+				return false
+			}
+		}
+		return true
+	default:
+		return q.isAnswer(answer, matcher)
+	}
+}
+
+// matcher returns the Matcher to compare the answers: the one of the Problem, the given fallback
+// if it has none or, if neither is set, exact comparison.
+func (q *Problem) matcher(fallback Matcher) Matcher {
+	if q.Matcher != nil {
+		return q.Matcher
+	}
+	if fallback != nil {
+		return fallback
+	}
+	return Exact{}
+}
+
+// isAnswer checks whether the given answer matches one of the accepted answers.
+func (q *Problem) isAnswer(answer string, matcher Matcher) bool {
+	for _, accepted := range q.Answers {
+		if matcher.Match(accepted, answer) {
+			return true
+		}
+	}
+	return false
+}
+
+// choice resolves the choice picked by the user, which can be referred by its number.
+func (q *Problem) choice(answer string) string {
+	answer = strings.TrimSpace(answer)
+	if n, err := strconv.Atoi(answer); err == nil && n >= 1 && n <= len(q.Choices) {
+		return q.Choices[n-1]
+	}
+	return answer
+}
+
+// selection resolves the distinct choices picked by the user in a comma separated answer.
+func (q *Problem) selection(answer string) []string {
+	seen := make(map[string]bool)
+	var selected []string
+
+	for _, part := range strings.Split(answer, ",") {
+		choice := q.choice(part)
+		if choice == "" || seen[choice] {
+			continue
+		}
+		seen[choice] = true
+		selected = append(selected, choice)
+	}
+
+	return selected
+}
+
+// prompt builds the question shown to the user by a runner.Runner.
+func (q *Problem) prompt() runner.Question {
+	return runner.Question{
+		Text:     q.Question,
+		Choices:  q.Choices,
+		Multiple: q.Kind == MultipleAnswer,
+		Markdown: q.Content.Markdown,
+		Code:     q.Content.Code,
+		Language: q.Content.Language,
+		Image:    q.Content.Image,
+	}
 }
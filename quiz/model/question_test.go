@@ -0,0 +1,58 @@
+package model
+
+import "testing"
+
+func checkAnswerTest(t *testing.T, problem Problem, answer string, expected bool) {
+	t.Helper()
+	if result := problem.CheckAnswer(answer); result != expected {
+		t.Errorf("Expected CheckAnswer(%q) to be %v for problem %+v, but got %v", answer, expected, problem, result)
+	}
+}
+
+func TestNewProblemInfersKind(t *testing.T) {
+	cases := map[Kind]Problem{
+		Open:           NewProblem("5+5", []string{"10"}, nil),
+		MultipleChoice: NewProblem("Capital of France?", []string{"Paris"}, []string{"London", "Paris"}),
+		MultipleAnswer: NewProblem("Primes?", []string{"2", "3"}, []string{"1", "2", "3", "4"}),
+	}
+
+	for expected, problem := range cases {
+		if problem.Kind != expected {
+			t.Errorf("Expected kind %v for problem %+v, but got %v", expected, problem, problem.Kind)
+		}
+	}
+}
+
+func TestCheckAnswerOpenAcceptsAnyAnswer(t *testing.T) {
+	problem := NewProblem("Big Apple?", []string{"NYC", "New York"}, nil)
+
+	checkAnswerTest(t, problem, "NYC", true)
+	checkAnswerTest(t, problem, " New York\n", true)
+	checkAnswerTest(t, problem, "Boston", false)
+}
+
+func TestCheckAnswerMultipleChoiceAcceptsNumberOrText(t *testing.T) {
+	problem := NewProblem("Capital of France?", []string{"Paris"}, []string{"London", "Paris", "Rome"})
+
+	checkAnswerTest(t, problem, "2", true)
+	checkAnswerTest(t, problem, "Paris", true)
+	checkAnswerTest(t, problem, "1", false)
+	checkAnswerTest(t, problem, "4", false)
+}
+
+func TestCheckAnswerMultipleAnswerRequiresAllChoices(t *testing.T) {
+	problem := NewProblem("Primes?", []string{"2", "3"}, []string{"1", "2", "3", "4"})
+
+	checkAnswerTest(t, problem, "2,3", true)
+	checkAnswerTest(t, problem, "3, 2, 3", true)
+	checkAnswerTest(t, problem, "2", false)
+	checkAnswerTest(t, problem, "2,3,4", false)
+}
+
+func TestCheckAnswerUsesProblemMatcher(t *testing.T) {
+	problem := NewProblem("Capital of France?", []string{"Paris"}, nil)
+	problem.Matcher = CaseInsensitive{}
+
+	checkAnswerTest(t, problem, "paris", true)
+	checkAnswerTest(t, problem, "london", false)
+}
@@ -1,76 +1,135 @@
 package model
 
 import (
-	"bufio"
-	"encoding/csv"
+	"context"
 	"math/rand"
-	"os"
+	"path/filepath"
+	"strings"
 	"time"
 
+	"github.com/roberveral/gophercises/quiz/report"
 	"github.com/roberveral/gophercises/quiz/runner"
-
-	"github.com/pkg/errors"
 )
 
 // Quiz is the representation of a set of questions which have to be answered.
 type Quiz struct {
+	// Title of the quiz, if any
+	Title    string
 	Problems []Problem
+	// Matcher used to compare the answers of the problems which don't define their own.
+	// When nil answers are compared exactly.
+	Matcher Matcher
+	// QuestionTimeout is the time the user has to answer each of the problems which don't
+	// define their own. When zero the questions are only limited by the time of the whole quiz.
+	QuestionTimeout time.Duration
+	// Scoring are the rules to score the answers
+	Scoring Scoring
+	// Feedback reveals the expected answers and their explanations to the user after each answer,
+	// through the Runners which are runner.FeedbackRunner
+	Feedback bool
+	// Seed of the random choices made to assemble the quiz, like shuffling it, reported in the
+	// results so the same quiz can be assembled again. Zero if none.
+	Seed int64
+	// Adaptive picks each problem by its difficulty, the closest to the ability of the user
+	// estimated from the previous answers, instead of asking them in order.
+	Adaptive bool
+	// MinAnswerTime is the least time in which a problem can be honestly answered. Faster answers
+	// are flagged in the report as suspicious of cheating. When zero no answer is flagged.
+	MinAnswerTime time.Duration
+	// OnAnswer, if set, is called with the entry of the report of each answer as soon as it is
+	// scored, so the answers are known even if the quiz is never completed, like for auditing.
+	OnAnswer func(report.Question)
 }
 
-// LoadFromCSV loads a Quiz from a CSV file which contains records with two fields, the question and the answer.
-func LoadFromCSV(path string) (*Quiz, error) {
-	file, err := os.Open(path)
-	if err != nil {
-		return nil, errors.Wrap(err, "Unable to open CSV file")
+// Execute executes the quiz asking the user for the answers.
+// It will show the questions in the given writer and will retrieve the answers from the given reader.
+// To complete the quiz the user has to answer the questions before the given timer goes off, so
+// all the unanswered questions are considered incorrect. A nil timer gives the quiz no time limit.
+// Besides, problems with a time limit are considered unanswered when the user doesn't answer them
+// in time, moving to the next one.
+// The quiz also ends, as if its time expired, when the given context is done.
+// Pending questions are cancelled through the context given to Runner.Ask, and Execute waits
+// for them to return, so no question outlives the quiz.
+// Adaptive quizzes ask harder problems while the user answers correctly and easier ones after
+// failing, reporting the ability of the user estimated as an Elo rating.
+// It returns the detailed report of the answers given by the user.
+func (q *Quiz) Execute(ctx context.Context, quizRunner runner.Runner, timer *time.Timer) *report.Report {
+	var expired <-chan time.Time
+	if timer != nil {
+		expired = timer.C
 	}
-	defer file.Close()
+	return NewSession(q, 0).run(ctx, quizRunner, expired, nil)
+}
 
-	reader := csv.NewReader(bufio.NewReader(file))
-	records, err := reader.ReadAll()
-	if err != nil {
-		return nil, errors.Wrap(err, "Malformed CSV file")
+// newReport returns an empty report of the quiz, before any answer is given.
+func (q *Quiz) newReport() *report.Report {
+	quizReport := &report.Report{Total: len(q.Problems), PassMark: q.Scoring.PassMark, Seed: q.Seed}
+	for _, problem := range q.Problems {
+		quizReport.MaxPoints += problem.MaxPoints()
 	}
+	return quizReport
+}
 
-	problems := make([]Problem, len(records))
-
-	for i, record := range records {
-		problems[i] = Problem{record[0], record[1]}
+// timeoutFor returns the time the user has to answer the given problem, zero if unlimited.
+func (q *Quiz) timeoutFor(problem *Problem) time.Duration {
+	if problem.Timeout > 0 {
+		return problem.Timeout
 	}
-
-	return &Quiz{problems}, nil
+	return q.QuestionTimeout
 }
 
-// Execute executes the quiz asking the user for the answers.
-// It will show the questions in the given writer and will retrieve the answers from the given reader.
-// To complete the quiz the user has to answer the questions before the given timer goes off, so
-// all the unanswered questions are considered incorrect
-func (q *Quiz) Execute(quizRunner runner.Runner, timer *time.Timer) {
-	total := len(q.Problems)
-	correct := 0
-	answerChannel := make(chan string)
+// tooFast checks whether an answer given in the given time is suspiciously fast. Blank answers
+// aren't, as they are given when the user gives up or disconnects.
+func (q *Quiz) tooFast(answer string, elapsed time.Duration) bool {
+	return q.MinAnswerTime > 0 && strings.TrimSpace(answer) != "" && elapsed < q.MinAnswerTime
+}
 
-	for i, problem := range q.Problems {
-		// Answer has to be processed in another goroutine so it can be dropped when the timer goes off
-		go func() { answerChannel <- quizRunner.Ask(i, problem.Question) }()
+// Copy returns a copy of the quiz which can be reordered independently, so the same quiz
+// can be taken by several users at once.
+func (q *Quiz) Copy() *Quiz {
+	quizCopy := *q
+	quizCopy.Problems = append([]Problem(nil), q.Problems...)
+	return &quizCopy
+}
 
-		// Let's see what happens first, either time runs out or the user places an answer in time
-		select {
-		case <-timer.C:
-			quizRunner.NotifyTimeout(correct, total)
-			return
-		case answer := <-answerChannel:
-			if problem.CheckAnswer(answer) {
-				correct++
-			}
+// ResolveImages makes the relative paths of the images of the problems relative to the given
+// directory, like the one of the quiz file, so they are found from any working directory.
+func (q *Quiz) ResolveImages(dir string) {
+	for i := range q.Problems {
+		image := q.Problems[i].Content.Image
+		if image != "" && !filepath.IsAbs(image) && !strings.Contains(image, "://") {
+			q.Problems[i].Content.Image = filepath.Join(dir, image)
 		}
 	}
-
-	quizRunner.ShowResults(correct, total)
 }
 
-// Shuffle reorders the quiz problems randomly
-func (q *Quiz) Shuffle() {
-	rand.Shuffle(len(q.Problems), func(i, j int) {
+// Shuffle reorders the quiz problems randomly with the given source of randomness, as well as
+// the choices of choice problems. Answers are tracked by the text of the choices, so they are
+// still correct after shuffling, and the choices are shuffled in a new slice so copies of the
+// quiz aren't affected.
+func (q *Quiz) Shuffle(rng *rand.Rand) {
+	rng.Shuffle(len(q.Problems), func(i, j int) {
 		q.Problems[i], q.Problems[j] = q.Problems[j], q.Problems[i]
 	})
+
+	for i := range q.Problems {
+		problem := &q.Problems[i]
+		if problem.Kind == Open || len(problem.Choices) < 2 {
+			continue
+		}
+		choices := append([]string(nil), problem.Choices...)
+		rng.Shuffle(len(choices), func(i, j int) {
+			choices[i], choices[j] = choices[j], choices[i]
+		})
+		problem.Choices = choices
+	}
+}
+
+// ShuffledCopy returns a copy of the quiz shuffled with the given seed, which is kept in the
+// copy so it is reported in the results.
+func (q *Quiz) ShuffledCopy(seed int64) *Quiz {
+	quizCopy := q.Copy()
+	quizCopy.Seed = seed
+	quizCopy.Shuffle(rand.New(rand.NewSource(seed)))
+	return quizCopy
 }
@@ -0,0 +1,250 @@
+package model
+
+import (
+	"context"
+	"math/rand"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/roberveral/gophercises/quiz/report"
+	"github.com/roberveral/gophercises/quiz/runner"
+)
+
+// scriptedRunner answers the questions with the given answers, and never answers
+// (blocking until the question is cancelled) when it runs out of them.
+type scriptedRunner struct {
+	answers []string
+	pending int32
+}
+
+func (r *scriptedRunner) Ask(ctx context.Context, number int, question runner.Question) string {
+	if number < len(r.answers) {
+		return r.answers[number]
+	}
+
+	atomic.AddInt32(&r.pending, 1)
+	defer atomic.AddInt32(&r.pending, -1)
+	<-ctx.Done()
+	return ""
+}
+
+func (r *scriptedRunner) ShowResults(quizReport *report.Report)   {}
+func (r *scriptedRunner) NotifyTimeout(quizReport *report.Report) {}
+func (r *scriptedRunner) NotifyQuestionTimeout(number int)        {}
+
+func testQuiz() *Quiz {
+	return &Quiz{Problems: []Problem{
+		NewProblem("5+5", []string{"10"}, nil),
+		NewProblem("1+1", []string{"2"}, nil),
+		NewProblem("2+2", []string{"4"}, nil),
+	}}
+}
+
+func TestExecuteReportsTheAnswers(t *testing.T) {
+	quizRunner := &scriptedRunner{answers: []string{"10\n", "3\n", "4\n"}}
+
+	quizReport := testQuiz().Execute(context.Background(), quizRunner, time.NewTimer(time.Minute))
+
+	if quizReport.Correct != 2 || quizReport.Total != 3 || quizReport.TimedOut {
+		t.Errorf("Expected 2 out of 3 correct answers in time, but got %+v", quizReport)
+	}
+	if answer := quizReport.Questions[1]; answer.Answer != "3" || answer.Correct {
+		t.Errorf("Expected the wrong answer to be reported, but got %+v", answer)
+	}
+}
+
+func TestExecuteCancelsExpiredQuestions(t *testing.T) {
+	quiz := testQuiz()
+	quiz.QuestionTimeout = 10 * time.Millisecond
+	quizRunner := &scriptedRunner{answers: []string{"10"}}
+
+	quizReport := quiz.Execute(context.Background(), quizRunner, time.NewTimer(time.Minute))
+
+	if quizReport.Correct != 1 || quizReport.TimedOut {
+		t.Errorf("Expected 1 correct answer in time, but got %+v", quizReport)
+	}
+	for _, question := range quizReport.Questions[1:] {
+		if !question.TimedOut {
+			t.Errorf("Expected question to be timed out, but got %+v", question)
+		}
+	}
+	if pending := atomic.LoadInt32(&quizRunner.pending); pending != 0 {
+		t.Errorf("Expected no pending questions after the quiz, but got %d", pending)
+	}
+}
+
+func TestExecuteCancelsPendingQuestionOnTimeout(t *testing.T) {
+	quizRunner := &scriptedRunner{answers: []string{"10"}}
+
+	quizReport := testQuiz().Execute(context.Background(), quizRunner, time.NewTimer(10*time.Millisecond))
+
+	if !quizReport.TimedOut || quizReport.Correct != 1 || len(quizReport.Questions) != 3 {
+		t.Errorf("Expected the quiz to time out with 1 correct answer, but got %+v", quizReport)
+	}
+	if pending := atomic.LoadInt32(&quizRunner.pending); pending != 0 {
+		t.Errorf("Expected no pending questions after the quiz, but got %d", pending)
+	}
+}
+
+func TestExecuteWithoutTimerHasNoTimeLimit(t *testing.T) {
+	quizRunner := &scriptedRunner{answers: []string{"10\n", "2\n", "4\n"}}
+
+	quizReport := testQuiz().Execute(context.Background(), quizRunner, nil)
+
+	if quizReport.TimedOut || quizReport.Correct != 3 {
+		t.Errorf("Expected 3 correct answers without time limit, but got %+v", quizReport)
+	}
+}
+
+func TestExecuteNotifiesEachAnswerAsItIsScored(t *testing.T) {
+	quiz := testQuiz()
+	var answers []report.Question
+	quiz.OnAnswer = func(question report.Question) { answers = append(answers, question) }
+	quizRunner := &scriptedRunner{answers: []string{"10", "3"}}
+
+	// The quiz is never completed, as the last question isn't answered before the timer goes off
+	quiz.Execute(context.Background(), quizRunner, time.NewTimer(50*time.Millisecond))
+
+	if len(answers) != 2 || !answers[0].Correct || answers[1].Correct || answers[1].Answer != "3" || answers[1].AnsweredAt.IsZero() {
+		t.Errorf("Expected the two answers given to be notified, but got %+v", answers)
+	}
+}
+
+// feedbackRunner is a scriptedRunner which records the feedback given.
+type feedbackRunner struct {
+	scriptedRunner
+	feedback []runner.Feedback
+}
+
+func (r *feedbackRunner) NotifyAnswer(feedback runner.Feedback) {
+	r.feedback = append(r.feedback, feedback)
+}
+
+func TestExecuteGivesFeedback(t *testing.T) {
+	quiz := testQuiz()
+	quiz.Problems[1].Explanation = "One plus one is two"
+	quiz.Problems[2].Timeout = 10 * time.Millisecond
+	quizRunner := &feedbackRunner{scriptedRunner: scriptedRunner{answers: []string{"10", "3"}}}
+
+	quiz.Execute(context.Background(), quizRunner, time.NewTimer(time.Minute))
+
+	// Timed out questions only get feedback in feedback mode
+	expected := []runner.Feedback{{Number: 0, Correct: true, Answer: "10"}, {Number: 1, Answer: "3"}}
+	if !reflect.DeepEqual(quizRunner.feedback, expected) {
+		t.Errorf("Expected feedback %+v, but got %+v", expected, quizRunner.feedback)
+	}
+
+	quiz.Feedback = true
+	quizRunner.feedback = nil
+	quiz.Execute(context.Background(), quizRunner, time.NewTimer(time.Minute))
+
+	expected = []runner.Feedback{
+		{Number: 0, Correct: true, Answer: "10", Expected: []string{"10"}},
+		{Number: 1, Answer: "3", Expected: []string{"2"}, Explanation: "One plus one is two"},
+		{Number: 2, TimedOut: true, Expected: []string{"4"}},
+	}
+	if !reflect.DeepEqual(quizRunner.feedback, expected) {
+		t.Errorf("Expected feedback %+v, but got %+v", expected, quizRunner.feedback)
+	}
+}
+
+func choiceQuiz() *Quiz {
+	return &Quiz{Problems: []Problem{
+		NewProblem("Capital of France?", []string{"Paris"}, []string{"London", "Paris", "Rome", "Berlin"}),
+		NewProblem("Primes?", []string{"2", "3"}, []string{"1", "2", "3", "4"}),
+		NewProblem("5+5", []string{"10"}, nil),
+	}}
+}
+
+func TestShuffleIsReproducible(t *testing.T) {
+	first, second := choiceQuiz(), choiceQuiz()
+	first.Shuffle(rand.New(rand.NewSource(7)))
+	second.Shuffle(rand.New(rand.NewSource(7)))
+
+	if !reflect.DeepEqual(first, second) {
+		t.Errorf("Expected the same order with the same seed, but got %+v and %+v", first.Problems, second.Problems)
+	}
+}
+
+func TestShuffledCopyShufflesChoicesKeepingTheAnswers(t *testing.T) {
+	quiz := choiceQuiz()
+
+	shuffled := false
+	for seed := int64(1); seed <= 10; seed++ {
+		quizCopy := quiz.ShuffledCopy(seed)
+		if quizCopy.Seed != seed {
+			t.Errorf("Expected the seed to be kept, but got %v", quizCopy.Seed)
+		}
+
+		for _, problem := range quizCopy.Problems {
+			switch problem.Question {
+			case "Capital of France?":
+				shuffled = shuffled || problem.Choices[1] != "Paris"
+				for i, choice := range problem.Choices {
+					if correct := problem.CheckAnswer(strconv.Itoa(i + 1)); correct != (choice == "Paris") {
+						t.Errorf("Expected choice %d (%s) to be correct only if Paris, but got %v", i+1, choice, correct)
+					}
+				}
+			case "Primes?":
+				var picks []string
+				for i, choice := range problem.Choices {
+					if choice == "2" || choice == "3" {
+						picks = append(picks, strconv.Itoa(i+1))
+					}
+				}
+				if !problem.CheckAnswer(strings.Join(picks, ",")) {
+					t.Errorf("Expected picks %v of %v to be correct", picks, problem.Choices)
+				}
+			}
+		}
+	}
+
+	if !shuffled {
+		t.Error("Expected the choices to be shuffled with some seed")
+	}
+	if !reflect.DeepEqual(quiz, choiceQuiz()) {
+		t.Errorf("Expected the original quiz not to change, but got %+v", quiz.Problems)
+	}
+}
+
+func TestResolveImagesRelativeToTheQuizFile(t *testing.T) {
+	quiz := &Quiz{Problems: []Problem{{Question: "a"}, {Question: "b"}, {Question: "c"}, {Question: "d"}}}
+	quiz.Problems[1].Content.Image = "images/gopher.png"
+	quiz.Problems[2].Content.Image = "/tmp/gopher.png"
+	quiz.Problems[3].Content.Image = "https://go.dev/gopher.png"
+
+	quiz.ResolveImages("quizzes")
+
+	expected := []string{"", "quizzes/images/gopher.png", "/tmp/gopher.png", "https://go.dev/gopher.png"}
+	for i, problem := range quiz.Problems {
+		if problem.Content.Image != expected[i] {
+			t.Errorf("Expected image %q, but got %q", expected[i], problem.Content.Image)
+		}
+	}
+}
+
+func TestExecuteFlagsAnswersTooFast(t *testing.T) {
+	quiz := testQuiz()
+	quiz.MinAnswerTime = time.Minute
+	quiz.QuestionTimeout = 10 * time.Millisecond
+	quizRunner := &scriptedRunner{answers: []string{"10", ""}}
+
+	before := time.Now()
+	quizReport := quiz.Execute(context.Background(), quizRunner, time.NewTimer(time.Minute))
+
+	if answer := quizReport.Questions[0]; !answer.TooFast || answer.AnsweredAt.Before(before) {
+		t.Errorf("Expected the instant answer to be flagged with its time, but got %+v", answer)
+	}
+	for _, answer := range quizReport.Questions[1:] {
+		if answer.TooFast {
+			t.Errorf("Expected blank and unanswered questions not to be flagged, but got %+v", answer)
+		}
+	}
+	if tooFast := quizReport.TooFast(); tooFast != 1 {
+		t.Errorf("Expected 1 answer too fast, but got %d", tooFast)
+	}
+}
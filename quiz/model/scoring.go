@@ -0,0 +1,98 @@
+package model
+
+import (
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Scoring are the rules to score the answers of a Quiz. The zero value awards the points of
+// each problem answered correctly, without penalties nor partial credit.
+type Scoring struct {
+	// Penalty is the fraction of the points of a problem deducted for a wrong answer, e.g. 0.25.
+	// Unanswered problems are never penalized.
+	Penalty float64
+	// PartialCredit awards MultipleAnswer problems a fraction of their points for each correct
+	// choice picked, minus each wrong one, instead of requiring all of them.
+	PartialCredit bool
+	// PassMark is the percentage of the maximum points needed to pass the quiz. When zero
+	// the quiz has no pass mark.
+	PassMark float64
+}
+
+// Validate checks that the scoring rules are in range: a penalty between 0 and 1 and a pass mark
+// between 0 and 100.
+func (s Scoring) Validate() error {
+	if !(s.Penalty >= 0 && s.Penalty <= 1) {
+		return errors.Errorf("Invalid penalty %v, expected a fraction between 0 and 1", s.Penalty)
+	}
+	if !(s.PassMark >= 0 && s.PassMark <= 100) {
+		return errors.Errorf("Invalid pass mark %v, expected a percentage between 0 and 100", s.PassMark)
+	}
+	return nil
+}
+
+// checkPoints checks that the points a problem is worth are a finite positive number, or zero
+// for the default of one point.
+func checkPoints(points float64) error {
+	if points < 0 || math.IsNaN(points) || math.IsInf(points, 0) {
+		return errors.Errorf("Invalid points %v, expected a positive number", points)
+	}
+	return nil
+}
+
+// parsePoints parses the points a problem is worth, as checked by checkPoints.
+func parsePoints(text string) (float64, error) {
+	points, err := strconv.ParseFloat(text, 64)
+	if err != nil || checkPoints(points) != nil {
+		return 0, errors.Errorf("Invalid points %q, expected a positive number", text)
+	}
+	return points, nil
+}
+
+// MaxPoints returns the points awarded for answering the problem correctly.
+func (q *Problem) MaxPoints() float64 {
+	if q.Points == 0 {
+		return 1
+	}
+	return q.Points
+}
+
+// score returns the points awarded to the given answer under the given scoring rules,
+// which are negative for penalized answers.
+func (q *Problem) score(answer string, fallback Matcher, scoring Scoring) float64 {
+	if q.checkAnswer(answer, fallback) {
+		return q.MaxPoints()
+	}
+
+	if scoring.PartialCredit && q.Kind == MultipleAnswer {
+		if credit := q.partialCredit(answer, q.matcher(fallback)); credit > 0 {
+			return credit * q.MaxPoints()
+		}
+	}
+
+	if strings.TrimSpace(answer) == "" {
+		return 0
+	}
+	return -scoring.Penalty * q.MaxPoints()
+}
+
+// partialCredit returns the fraction of the correct choices picked in the answer, minus the
+// wrong ones, or zero if there are more wrong choices than correct ones.
+func (q *Problem) partialCredit(answer string, matcher Matcher) float64 {
+	credit := 0
+	for _, choice := range q.selection(answer) {
+		if q.isAnswer(choice, matcher) {
+			credit++
+		} else {
+			credit--
+		}
+	}
+
+	if credit <= 0 || len(q.Answers) == 0 {
+		return 0
+	}
+	return float64(credit) / float64(len(q.Answers))
+}
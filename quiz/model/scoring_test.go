@@ -0,0 +1,59 @@
+package model
+
+import (
+	"math"
+	"testing"
+)
+
+func scoreTest(t *testing.T, problem Problem, scoring Scoring, answer string, expected float64) {
+	t.Helper()
+	if points := problem.score(answer, nil, scoring); points != expected {
+		t.Errorf("Expected %v points for answer %q with %+v, but got %v", expected, answer, scoring, points)
+	}
+}
+
+func TestScoreAwardsProblemPoints(t *testing.T) {
+	problem := NewProblem("5+5", []string{"10"}, nil)
+	scoreTest(t, problem, Scoring{}, "10", 1)
+
+	problem.Points = 3
+	scoreTest(t, problem, Scoring{}, "10", 3)
+	scoreTest(t, problem, Scoring{}, "11", 0)
+}
+
+func TestScorePenalizesOnlyWrongAnswers(t *testing.T) {
+	problem := NewProblem("5+5", []string{"10"}, nil)
+	problem.Points = 2
+	scoring := Scoring{Penalty: 0.25}
+
+	scoreTest(t, problem, scoring, "11", -0.5)
+	scoreTest(t, problem, scoring, "  \n", 0)
+	scoreTest(t, problem, scoring, "10", 2)
+}
+
+func TestScoreAwardsPartialCredit(t *testing.T) {
+	problem := NewProblem("Primes?", []string{"2", "3", "5"}, []string{"1", "2", "3", "4", "5"})
+	problem.Points = 3
+	scoring := Scoring{Penalty: 1, PartialCredit: true}
+
+	scoreTest(t, problem, scoring, "2,3", 2)
+	scoreTest(t, problem, scoring, "2,3,4", 1)
+	scoreTest(t, problem, scoring, "2,4", -3)
+	scoreTest(t, problem, Scoring{}, "2,3", 0)
+}
+
+func TestScoringValidate(t *testing.T) {
+	valid := []Scoring{{}, {Penalty: 1, PassMark: 100}, {Penalty: 0.25, PassMark: 50}}
+	for _, scoring := range valid {
+		if err := scoring.Validate(); err != nil {
+			t.Errorf("Expected %+v to be valid, but an error was returned: %+v", scoring, err)
+		}
+	}
+
+	invalid := []Scoring{{Penalty: -0.5}, {Penalty: 1.5}, {PassMark: -1}, {PassMark: 101}, {PassMark: math.NaN()}}
+	for _, scoring := range invalid {
+		if err := scoring.Validate(); err == nil {
+			t.Errorf("Expected an error for %+v", scoring)
+		}
+	}
+}
@@ -0,0 +1,167 @@
+package model
+
+import (
+	"math/rand"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Selection are the criteria to assemble a quiz from a bank of problems.
+type Selection struct {
+	// Tags of the problems to select, matching either their category or any of their tags.
+	// When empty all the problems are selected.
+	Tags []string
+	// Sample is the number of problems to pick at random among the selected ones. When zero
+	// all of them are picked.
+	Sample int
+	// Stratify spreads the sample evenly among the tags, so each of them is equally
+	// represented. Without Tags, the sample is spread among all the tags in the bank.
+	Stratify bool
+}
+
+// HasTag checks whether the problem has the given tag or belongs to the given category,
+// ignoring case.
+func (q *Problem) HasTag(tag string) bool {
+	tag = strings.TrimSpace(tag)
+	if strings.EqualFold(q.Category, tag) && q.Category != "" {
+		return true
+	}
+	for _, problemTag := range q.Tags {
+		if strings.EqualFold(problemTag, tag) {
+			return true
+		}
+	}
+	return false
+}
+
+// Select assembles a quiz with the problems of this one which meet the given selection,
+// picking them at random with the given source of randomness so the same quiz can be assembled
+// again with the same seed. The problems keep the order they have in this quiz.
+func (q *Quiz) Select(selection Selection, rng *rand.Rand) (*Quiz, error) {
+	var candidates []int
+	for i := range q.Problems {
+		if len(selection.Tags) == 0 || q.Problems[i].hasAnyTag(selection.Tags) {
+			candidates = append(candidates, i)
+		}
+	}
+	if len(candidates) == 0 {
+		return nil, errors.Errorf("No problems match the tags %v", selection.Tags)
+	}
+
+	picked := candidates
+	if selection.Sample > 0 {
+		if selection.Sample > len(candidates) {
+			return nil, errors.Errorf("Unable to sample %d problems, only %d match", selection.Sample, len(candidates))
+		}
+		if selection.Stratify {
+			picked = q.stratifiedSample(candidates, q.strata(selection.Tags), selection.Sample, rng)
+		} else {
+			picked = sample(candidates, selection.Sample, rng)
+		}
+	}
+
+	selected := *q
+	selected.Problems = make([]Problem, len(picked))
+	for i, index := range picked {
+		selected.Problems[i] = q.Problems[index]
+	}
+	return &selected, nil
+}
+
+func (q *Problem) hasAnyTag(tags []string) bool {
+	for _, tag := range tags {
+		if q.HasTag(tag) {
+			return true
+		}
+	}
+	return false
+}
+
+// strata returns the tags to spread a stratified sample among: the given ones or, if none,
+// all the categories and tags of the problems in order of appearance. Problems without any
+// of them make up the stratum of the empty tag.
+func (q *Quiz) strata(tags []string) []string {
+	if len(tags) > 0 {
+		return tags
+	}
+
+	var strata []string
+	seen := make(map[string]bool)
+	untagged := false
+	for _, problem := range q.Problems {
+		problemTags := problem.Tags
+		if problem.Category != "" {
+			problemTags = append([]string{problem.Category}, problemTags...)
+		}
+		untagged = untagged || len(problemTags) == 0
+
+		for _, tag := range problemTags {
+			if key := strings.ToLower(tag); !seen[key] {
+				seen[key] = true
+				strata = append(strata, tag)
+			}
+		}
+	}
+
+	if untagged {
+		strata = append(strata, "")
+	}
+	return strata
+}
+
+// stratifiedSample picks the given number of the candidate problems, spreading them evenly
+// among the given tags. When a tag doesn't have enough problems its share is picked among
+// the rest of the candidates.
+func (q *Quiz) stratifiedSample(candidates []int, strata []string, size int, rng *rand.Rand) []int {
+	picked := make(map[int]bool)
+	for i, tag := range strata {
+		share := size / len(strata)
+		if i < size%len(strata) {
+			share++
+		}
+
+		var stratum []int
+		for _, index := range candidates {
+			problem := &q.Problems[index]
+			inStratum := problem.HasTag(tag) || (tag == "" && problem.Category == "" && len(problem.Tags) == 0)
+			if inStratum && !picked[index] {
+				stratum = append(stratum, index)
+			}
+		}
+		if share > len(stratum) {
+			share = len(stratum)
+		}
+		for _, index := range sample(stratum, share, rng) {
+			picked[index] = true
+		}
+	}
+
+	var rest []int
+	for _, index := range candidates {
+		if !picked[index] {
+			rest = append(rest, index)
+		}
+	}
+	for _, index := range sample(rest, size-len(picked), rng) {
+		picked[index] = true
+	}
+
+	indexes := make([]int, 0, len(picked))
+	for index := range picked {
+		indexes = append(indexes, index)
+	}
+	sort.Ints(indexes)
+	return indexes
+}
+
+// sample picks the given number of the given indexes at random, keeping their order.
+func sample(indexes []int, size int, rng *rand.Rand) []int {
+	picked := make([]int, size)
+	for i, position := range rng.Perm(len(indexes))[:size] {
+		picked[i] = indexes[position]
+	}
+	sort.Ints(picked)
+	return picked
+}
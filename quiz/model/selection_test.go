@@ -0,0 +1,107 @@
+package model
+
+import (
+	"math/rand"
+	"reflect"
+	"testing"
+)
+
+func bank() *Quiz {
+	problem := func(question, category string, tags ...string) Problem {
+		p := NewProblem(question, []string{"x"}, nil)
+		p.Category, p.Tags = category, tags
+		return p
+	}
+
+	return &Quiz{Title: "Bank", Problems: []Problem{
+		problem("a1", "algebra"),
+		problem("a2", "algebra", "easy"),
+		problem("a3", "algebra"),
+		problem("a4", "algebra"),
+		problem("a5", "algebra"),
+		problem("g1", "geometry"),
+		problem("g2", "geometry", "easy"),
+		problem("u1", ""),
+	}}
+}
+
+func selectedQuestions(quiz *Quiz) []string {
+	var questions []string
+	for _, problem := range quiz.Problems {
+		questions = append(questions, problem.Question)
+	}
+	return questions
+}
+
+func countTag(quiz *Quiz, tag string) int {
+	count := 0
+	for _, problem := range quiz.Problems {
+		if problem.HasTag(tag) {
+			count++
+		}
+	}
+	return count
+}
+
+func TestSelectByTags(t *testing.T) {
+	selected, err := bank().Select(Selection{Tags: []string{"Geometry", "easy"}}, rand.New(rand.NewSource(1)))
+	if err != nil {
+		t.Fatalf("Expected valid result, but an error was returned: %+v", err)
+	}
+
+	if expected := []string{"a2", "g1", "g2"}; !reflect.DeepEqual(selectedQuestions(selected), expected) {
+		t.Errorf("Expected problems %v, but got %v", expected, selectedQuestions(selected))
+	}
+	if selected.Title != "Bank" {
+		t.Errorf("Expected the settings of the quiz to be kept, but got %+v", selected)
+	}
+}
+
+func TestSelectSampleIsReproducible(t *testing.T) {
+	first, _ := bank().Select(Selection{Sample: 4}, rand.New(rand.NewSource(42)))
+	second, _ := bank().Select(Selection{Sample: 4}, rand.New(rand.NewSource(42)))
+
+	if len(first.Problems) != 4 {
+		t.Errorf("Expected 4 problems, but got %v", selectedQuestions(first))
+	}
+	if !reflect.DeepEqual(selectedQuestions(first), selectedQuestions(second)) {
+		t.Errorf("Expected the same sample with the same seed, but got %v and %v", selectedQuestions(first), selectedQuestions(second))
+	}
+}
+
+func TestSelectStratifiedSample(t *testing.T) {
+	for seed := int64(0); seed < 20; seed++ {
+		selected, err := bank().Select(Selection{Tags: []string{"algebra", "geometry"}, Sample: 4, Stratify: true}, rand.New(rand.NewSource(seed)))
+		if err != nil {
+			t.Fatalf("Expected valid result, but an error was returned: %+v", err)
+		}
+		if algebra, geometry := countTag(selected, "algebra"), countTag(selected, "geometry"); algebra != 2 || geometry != 2 {
+			t.Errorf("Expected 2 problems of each tag, but got %v", selectedQuestions(selected))
+		}
+	}
+}
+
+func TestSelectStratifiedSampleSpreadsAmongAllTags(t *testing.T) {
+	for seed := int64(0); seed < 20; seed++ {
+		// Each of algebra, geometry, easy and untagged problems gets one of the four problems
+		selected, err := bank().Select(Selection{Sample: 4, Stratify: true}, rand.New(rand.NewSource(seed)))
+		if err != nil {
+			t.Fatalf("Expected valid result, but an error was returned: %+v", err)
+		}
+
+		questions := selectedQuestions(selected)
+		if len(questions) != 4 || questions[3] != "u1" || countTag(selected, "geometry") == 0 || countTag(selected, "easy") == 0 {
+			t.Errorf("Expected problems of every tag, but got %v", questions)
+		}
+	}
+}
+
+func TestSelectInvalid(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	if _, err := bank().Select(Selection{Tags: []string{"history"}}, rng); err == nil {
+		t.Error("Expected an error when no problems match")
+	}
+	if _, err := bank().Select(Selection{Tags: []string{"geometry"}, Sample: 3}, rng); err == nil {
+		t.Error("Expected an error when the sample is bigger than the problems")
+	}
+}
@@ -0,0 +1,261 @@
+package model
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/roberveral/gophercises/quiz/report"
+	"github.com/roberveral/gophercises/quiz/runner"
+)
+
+// Session is a quiz in progress: the problems in the order they are asked, the answers given
+// so far and the time remaining to complete it. It can be saved after each answer, so the
+// quiz can be resumed from where it was left if it is interrupted.
+type Session struct {
+	// Quiz taken, with its problems in the order they are asked
+	Quiz *Quiz
+	// Next is the index of the next problem to ask
+	Next int
+	// Report of the answers given so far
+	Report *report.Report
+	// Remaining is the time left to complete the quiz. When zero the quiz has no time limit.
+	Remaining time.Duration
+	// Ability of the user estimated from the answers given so far, for adaptive quizzes
+	Ability float64
+}
+
+// NewSession starts a session of a copy of the given quiz, which has to be completed in the
+// given time, or without time limit if zero.
+func NewSession(quiz *Quiz, timeout time.Duration) *Session {
+	return &Session{Quiz: quiz.Copy(), Report: quiz.newReport(), Remaining: timeout, Ability: DefaultRating}
+}
+
+// Finished checks whether all the problems of the session have been asked.
+func (s *Session) Finished() bool {
+	return s.Next >= len(s.Quiz.Problems)
+}
+
+// Run asks the user the pending problems of the session, as Quiz.Execute does, with the time
+// remaining in the session. When the Runner is a runner.Pauser the user can answer
+// runner.PauseCommand to stop the time until the quiz is resumed.
+// The given checkpoint, if any, is called with the session after each answer and when the
+// quiz is paused, so the progress can be saved.
+// It returns the detailed report of the answers given by the user in the whole session.
+func (s *Session) Run(ctx context.Context, quizRunner runner.Runner, checkpoint func(*Session)) *report.Report {
+	return s.run(ctx, quizRunner, nil, checkpoint)
+}
+
+// run asks the pending problems of the session until they are all answered or the time of the
+// session, or the given timer, expires. Quizzes can only be paused when there isn't a timer.
+func (s *Session) run(ctx context.Context, quizRunner runner.Runner, timer <-chan time.Time, checkpoint func(*Session)) *report.Report {
+	q := s.Quiz
+	quizReport := s.Report
+	quizTime := startCountdown(s.Remaining)
+	defer quizTime.stop()
+
+	pauser, canPause := quizRunner.(runner.Pauser)
+	canPause = canPause && timer == nil
+	feedbackRunner, giveFeedback := quizRunner.(runner.FeedbackRunner)
+
+	for !s.Finished() {
+		i := s.Next
+		if q.Adaptive {
+			pickNext(q.Problems[i:], s.Ability)
+		}
+		problem := q.Problems[i]
+		questionTime := startCountdown(q.timeoutFor(&problem))
+
+		entry := report.Question{Number: i, Question: problem.Question, Expected: problem.Answers, MaxPoints: problem.MaxPoints()}
+		answered, timedOut := false, false
+
+		for {
+			answerChannel := make(chan string, 1)
+			askCtx, cancel := context.WithCancel(ctx)
+			start := time.Now()
+
+			question := problem.prompt()
+			question.Total = len(q.Problems)
+			question.Remaining, question.QuestionRemaining = quizTime.left(), questionTime.left()
+
+			// Answer has to be processed in another goroutine so it can be dropped when the timer goes off
+			go func(number int, question runner.Question) {
+				answerChannel <- quizRunner.Ask(askCtx, number, question)
+			}(i, question)
+
+			var answer string
+			// Let's see what happens first, either time runs out or the user places an answer in time
+			select {
+			case <-timer:
+				timedOut = true
+			case <-quizTime.expired():
+				timedOut = true
+			case <-ctx.Done():
+				timedOut = true
+			case <-questionTime.expired():
+				entry.TimedOut = true
+			case answer = <-answerChannel:
+				answered = true
+			}
+
+			entry.Elapsed += time.Since(start)
+			cancel()
+			if !answered {
+				// The question was dropped, so wait for the runner to give up on it
+				<-answerChannel
+			}
+
+			if answered && canPause && strings.TrimSpace(answer) == runner.PauseCommand {
+				// The time is stopped while paused, and the question is asked again on resume
+				quizTime.pause()
+				questionTime.pause()
+				s.Remaining = quizTime.left()
+				if checkpoint != nil {
+					checkpoint(s)
+				}
+				pauser.Pause(ctx)
+				if answered = false; ctx.Err() != nil {
+					timedOut = true
+					break
+				}
+				quizTime.resume()
+				questionTime.resume()
+				continue
+			}
+
+			if answered {
+				entry.Answer = strings.TrimSpace(answer)
+				entry.Correct = problem.checkAnswer(answer, q.Matcher)
+				entry.Points = problem.score(answer, q.Matcher, q.Scoring)
+				entry.AnsweredAt = time.Now()
+				entry.TooFast = q.tooFast(answer, entry.Elapsed)
+			}
+			break
+		}
+		questionTime.stop()
+		if answered && q.OnAnswer != nil {
+			q.OnAnswer(entry)
+		}
+
+		if timedOut {
+			quizReport.TimedOut = true
+			for _, unanswered := range q.Problems[i:] {
+				quizReport.Questions = append(quizReport.Questions, report.Question{
+					Number:    len(quizReport.Questions),
+					Question:  unanswered.Question,
+					Expected:  unanswered.Answers,
+					MaxPoints: unanswered.MaxPoints(),
+					TimedOut:  true,
+				})
+			}
+			quizReport.Questions[i].Elapsed = entry.Elapsed
+			s.Next, s.Remaining = len(q.Problems), 0
+			if q.Adaptive {
+				quizReport.Ability = s.Ability
+			}
+			if checkpoint != nil {
+				checkpoint(s)
+			}
+			quizRunner.NotifyTimeout(quizReport)
+			return quizReport
+		}
+
+		if entry.TimedOut {
+			quizRunner.NotifyQuestionTimeout(i)
+		}
+		if giveFeedback && (!entry.TimedOut || q.Feedback) {
+			feedback := runner.Feedback{Number: i, Correct: entry.Correct, TimedOut: entry.TimedOut, Answer: entry.Answer}
+			if q.Feedback {
+				feedback.Expected, feedback.Explanation = problem.Answers, problem.Explanation
+			}
+			feedbackRunner.NotifyAnswer(feedback)
+		}
+
+		if entry.Correct {
+			quizReport.Correct++
+		}
+		quizReport.Points += entry.Points
+		quizReport.Questions = append(quizReport.Questions, entry)
+		s.Ability = updateAbility(s.Ability, problem.Rating(), scoreFraction(entry.Points, entry.MaxPoints))
+		s.Next++
+		s.Remaining = quizTime.left()
+		if checkpoint != nil {
+			checkpoint(s)
+		}
+	}
+
+	if q.Adaptive {
+		quizReport.Ability = s.Ability
+	}
+	quizRunner.ShowResults(quizReport)
+	return quizReport
+}
+
+// sessionDocument is the JSON representation of a Session.
+type sessionDocument struct {
+	Quiz      quizDocument   `json:"quiz"`
+	Next      int            `json:"next"`
+	Report    *report.Report `json:"report"`
+	Remaining string         `json:"remaining,omitempty"`
+	Ability   float64        `json:"ability,omitempty"`
+}
+
+// Save stores the session as JSON in the file in the given path, replacing the previous
+// checkpoint with ReplaceFile.
+func (s *Session) Save(path string) error {
+	quizDoc, err := newQuizDocument(s.Quiz)
+	if err != nil {
+		return errors.Wrap(err, "Unable to encode session")
+	}
+
+	document := sessionDocument{Quiz: quizDoc, Next: s.Next, Report: s.Report, Ability: s.Ability}
+	if s.Remaining > 0 {
+		document.Remaining = s.Remaining.Round(time.Millisecond).String()
+	}
+
+	data, err := json.MarshalIndent(document, "", "  ")
+	if err != nil {
+		return errors.Wrap(err, "Unable to encode session")
+	}
+
+	return errors.Wrap(ReplaceFile(path, data), "Unable to write session file")
+}
+
+// LoadSession loads a Session saved with Save from the file in the given path. Finished sessions
+// can't be loaded, as their quiz has already been completed.
+func LoadSession(path string) (*Session, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, errors.Wrap(err, "Unable to open session file")
+	}
+	defer file.Close()
+
+	var document sessionDocument
+	if err := json.NewDecoder(file).Decode(&document); err != nil {
+		return nil, errors.Wrap(err, "Malformed session file")
+	}
+
+	quiz, err := document.Quiz.quiz()
+	if err != nil {
+		return nil, errors.Wrap(err, "Malformed session file")
+	}
+	if document.Report == nil || document.Next < 0 || document.Next > len(quiz.Problems) || len(document.Report.Questions) != document.Next {
+		return nil, errors.New("Malformed session file: inconsistent progress")
+	}
+	if document.Next == len(quiz.Problems) {
+		return nil, errors.New("The quiz of the session file is already finished")
+	}
+
+	session := &Session{Quiz: quiz, Next: document.Next, Report: document.Report, Ability: document.Ability}
+	if session.Remaining, err = parseOptionalDuration(document.Remaining); err != nil {
+		return nil, errors.Wrap(err, "Malformed session file")
+	}
+	if session.Ability == 0 {
+		session.Ability = DefaultRating
+	}
+	return session, nil
+}
@@ -0,0 +1,121 @@
+package model
+
+import (
+	"context"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/roberveral/gophercises/quiz/report"
+	"github.com/roberveral/gophercises/quiz/runner"
+)
+
+// pausingRunner pauses the quiz before answering each question, staying paused for the
+// given time, and records the sessions it is checkpointed with.
+type pausingRunner struct {
+	scriptedRunner
+	pauseFor time.Duration
+	paused   map[int]bool
+}
+
+func (r *pausingRunner) Ask(ctx context.Context, number int, question runner.Question) string {
+	if !r.paused[number] {
+		r.paused[number] = true
+		return runner.PauseCommand
+	}
+	return r.scriptedRunner.Ask(ctx, number, question)
+}
+
+func (r *pausingRunner) Pause(ctx context.Context) {
+	select {
+	case <-time.After(r.pauseFor):
+	case <-ctx.Done():
+	}
+}
+
+func TestSessionRunStopsTheTimeWhilePaused(t *testing.T) {
+	quizRunner := &pausingRunner{
+		scriptedRunner: scriptedRunner{answers: []string{"10", "2", "4"}},
+		pauseFor:       30 * time.Millisecond,
+		paused:         make(map[int]bool),
+	}
+
+	var checkpoints []int
+	session := NewSession(testQuiz(), 50*time.Millisecond)
+	quizReport := session.Run(context.Background(), quizRunner, func(s *Session) {
+		checkpoints = append(checkpoints, s.Next)
+	})
+
+	if quizReport.TimedOut || quizReport.Correct != 3 {
+		t.Errorf("Expected 3 correct answers in time despite the pauses, but got %+v", quizReport)
+	}
+	// Each question is checkpointed when paused and when answered
+	if expected := []int{0, 1, 1, 2, 2, 3}; !reflect.DeepEqual(checkpoints, expected) {
+		t.Errorf("Expected checkpoints %v, but got %v", expected, checkpoints)
+	}
+	if !session.Finished() {
+		t.Errorf("Expected session to be finished, but it is at %d", session.Next)
+	}
+}
+
+func TestExecuteTreatsPauseAsAnswer(t *testing.T) {
+	quizRunner := &pausingRunner{scriptedRunner: scriptedRunner{}, paused: make(map[int]bool)}
+
+	quizReport := testQuiz().Execute(context.Background(), quizRunner, time.NewTimer(time.Minute))
+
+	if answer := quizReport.Questions[0].Answer; answer != runner.PauseCommand {
+		t.Errorf("Expected the pause command to be an answer when the timer can't be paused, but got %q", answer)
+	}
+}
+
+func TestSessionSaveAndResume(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "session.json")
+	quiz := testQuiz()
+	quiz.Matcher = Numeric{Tolerance: 0.5}
+	quiz.Problems[1].Timeout = 5 * time.Second
+
+	// The session is interrupted after the first answer
+	session := NewSession(quiz, time.Minute)
+	session.Next = 1
+	session.Remaining = 42 * time.Second
+	session.Report.Questions = []report.Question{{Number: 0, Question: "5+5", Expected: []string{"10"}, Answer: "10", Correct: true, Points: 1, MaxPoints: 1}}
+	session.Report.Correct, session.Report.Points = 1, 1
+	if err := session.Save(path); err != nil {
+		t.Fatalf("Expected valid result, but an error was returned: %+v", err)
+	}
+
+	resumed, err := LoadSession(path)
+	if err != nil {
+		t.Fatalf("Expected valid result, but an error was returned: %+v", err)
+	}
+	if !reflect.DeepEqual(resumed, session) {
+		t.Errorf("Expected session %+v, but got %+v", session, resumed)
+	}
+
+	quizReport := resumed.Run(context.Background(), &scriptedRunner{answers: []string{"", "2.2", "4"}}, nil)
+	if quizReport.Correct != 3 || len(quizReport.Questions) != 3 {
+		t.Errorf("Expected 3 correct answers after resuming, but got %+v", quizReport)
+	}
+}
+
+func TestLoadSessionRejectsInconsistentProgress(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "session.json")
+	session := NewSession(testQuiz(), 0)
+	session.Next = 2
+	session.Save(path)
+
+	if _, err := LoadSession(path); err == nil {
+		t.Error("Expected an error for a session without the answers of the asked problems")
+	}
+}
+
+func TestLoadSessionRejectsFinishedSessions(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "session.json")
+	session := NewSession(testQuiz(), 0)
+	session.Run(context.Background(), &scriptedRunner{answers: []string{"10", "2", "4"}}, func(s *Session) { s.Save(path) })
+
+	if _, err := LoadSession(path); err == nil {
+		t.Error("Expected an error for a finished session")
+	}
+}
@@ -0,0 +1,83 @@
+package model
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/roberveral/gophercises/quiz/expr"
+)
+
+// Issue is a mistake found in a problem of a quiz by Validate.
+type Issue struct {
+	// Problem is the number of the problem in the quiz, starting at 1
+	Problem int
+	// Question of the problem
+	Question string
+	// Message describing the mistake
+	Message string
+}
+
+func (i Issue) String() string {
+	return fmt.Sprintf("problem %d (%s): %s", i.Problem, i.Question, i.Message)
+}
+
+// Validate looks for mistakes which the loaders accept but are likely errors of the author
+// of the quiz: questions asked twice, empty accepted answers and arithmetic questions, like
+// "5+5" or "What is 5+5?", whose answers don't evaluate to the value of the expression.
+func (q *Quiz) Validate() []Issue {
+	var issues []Issue
+	first := make(map[string]int)
+
+	for i := range q.Problems {
+		problem := &q.Problems[i]
+		report := func(format string, args ...interface{}) {
+			issues = append(issues, Issue{i + 1, problem.Question, fmt.Sprintf(format, args...)})
+		}
+
+		key := strings.ToLower(strings.Join(strings.Fields(problem.Question), " "))
+		if previous, ok := first[key]; ok {
+			report("Duplicated question, already asked in problem %d", previous)
+		} else {
+			first[key] = i + 1
+		}
+
+		for _, answer := range problem.Answers {
+			if strings.TrimSpace(answer) == "" {
+				report("Empty answer")
+			}
+		}
+
+		if value, ok := arithmeticValue(problem.Question); ok && !hasValue(problem.Answers, value) {
+			report("Expected answer %s, but got %q", strconv.FormatFloat(value, 'f', -1, 64), strings.Join(problem.Answers, "|"))
+		}
+	}
+	return issues
+}
+
+// arithmeticValue evaluates the expression asked in an arithmetic question, if it is one.
+func arithmeticValue(question string) (float64, bool) {
+	expression := strings.TrimSpace(question)
+	if strings.HasPrefix(strings.ToLower(expression), "what is ") {
+		expression = expression[len("what is "):]
+	}
+	expression = strings.TrimSpace(strings.TrimRight(expression, "?= "))
+
+	if !strings.ContainsAny(strings.TrimLeft(expression, "- "), "+-*/") {
+		return 0, false
+	}
+	value, err := expr.Evaluate(expression)
+	return value, err == nil
+}
+
+// hasValue checks whether any of the answers is a number equal to the given value.
+func hasValue(answers []string, value float64) bool {
+	for _, answer := range answers {
+		number, err := strconv.ParseFloat(strings.TrimSpace(answer), 64)
+		if err == nil && math.Abs(number-value) <= 1e-9*math.Max(1, math.Abs(value)) {
+			return true
+		}
+	}
+	return false
+}
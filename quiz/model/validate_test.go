@@ -0,0 +1,28 @@
+package model
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestValidateReportsMistakes(t *testing.T) {
+	quiz := &Quiz{Problems: []Problem{
+		NewProblem("5+5", []string{"10"}, nil),
+		NewProblem("What is 3*4?", []string{"13"}, nil),
+		NewProblem("Capital of France?", []string{"Paris", ""}, nil),
+		NewProblem("capital of  France?", []string{"Paris"}, nil),
+		NewProblem("(7-3)/2 =", []string{"two", "2"}, nil),
+		NewProblem("Year of the moon landing?", []string{"1969"}, nil),
+	}}
+
+	issues := quiz.Validate()
+
+	expected := []Issue{
+		{2, "What is 3*4?", `Expected answer 12, but got "13"`},
+		{3, "Capital of France?", "Empty answer"},
+		{4, "capital of  France?", "Duplicated question, already asked in problem 3"},
+	}
+	if !reflect.DeepEqual(issues, expected) {
+		t.Errorf("Expected issues %v, but got %v", expected, issues)
+	}
+}
@@ -0,0 +1,121 @@
+package practice
+
+import (
+	"encoding/json"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/roberveral/gophercises/quiz/model"
+	"github.com/roberveral/gophercises/quiz/report"
+)
+
+// History is the practice record of the questions of one or more quizzes, keyed by the
+// text of the question.
+type History struct {
+	Cards map[string]*Card `json:"cards"`
+}
+
+// NewHistory creates an empty History.
+func NewHistory() *History {
+	return &History{Cards: make(map[string]*Card)}
+}
+
+// LoadHistory loads the History stored as JSON in the file in the given path. A missing file
+// is an empty History, so practice can start from scratch.
+func LoadHistory(path string) (*History, error) {
+	file, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return NewHistory(), nil
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "Unable to open practice history")
+	}
+	defer file.Close()
+
+	history := NewHistory()
+	if err := json.NewDecoder(file).Decode(history); err != nil {
+		return nil, errors.Wrap(err, "Malformed practice history")
+	}
+	if history.Cards == nil {
+		history.Cards = make(map[string]*Card)
+	}
+	return history, nil
+}
+
+// Save stores the History as JSON in the file in the given path, replacing it with
+// model.ReplaceFile.
+func (h *History) Save(path string) error {
+	data, err := json.MarshalIndent(h, "", "  ")
+	if err != nil {
+		return errors.Wrap(err, "Unable to encode practice history")
+	}
+	return errors.Wrap(model.ReplaceFile(path, data), "Unable to write practice history")
+}
+
+// Card returns the Card of the given question, or nil if it has never been practiced.
+func (h *History) Card(question string) *Card {
+	return h.Cards[question]
+}
+
+// Order reorders the problems of the quiz to practice the ones due at the given time first,
+// the most overdue of them before the rest. They are followed by the problems which have
+// never been practiced, in their original order, and finally by the ones which aren't due
+// yet, the closest to be due first.
+func (h *History) Order(quiz *model.Quiz, now time.Time) {
+	sort.SliceStable(quiz.Problems, func(i, j int) bool {
+		a, b := h.Card(quiz.Problems[i].Question), h.Card(quiz.Problems[j].Question)
+		rankA, rankB := h.rank(a, now), h.rank(b, now)
+		if rankA != rankB {
+			return rankA < rankB
+		}
+		return a != nil && b != nil && a.Due.Before(b.Due)
+	})
+}
+
+// rank groups the problems for Order: due (0), new (1) and not due yet (2).
+func (h *History) rank(card *Card, now time.Time) int {
+	switch {
+	case card == nil:
+		return 1
+	case !card.Due.After(now):
+		return 0
+	default:
+		return 2
+	}
+}
+
+// Record reviews the questions answered in the given report at the given time, scheduling
+// their next review. Questions left unanswered because the time of the quiz expired
+// haven't been practiced, so they are left as they were.
+func (h *History) Record(quizReport *report.Report, now time.Time) {
+	for _, question := range quizReport.Questions {
+		if quizReport.TimedOut && question.TimedOut && question.Answer == "" {
+			continue
+		}
+
+		card := h.Cards[question.Question]
+		if card == nil {
+			card = newCard()
+			h.Cards[question.Question] = card
+		}
+		card.Review(Review{Time: now, Answer: question.Answer, Correct: question.Correct, Quality: Quality(question)})
+	}
+}
+
+// Quality grades an answer for SM-2: 5 for a correct answer, 3 for a partially correct
+// one, 1 for a wrong answer and 0 for a question left unanswered.
+func Quality(question report.Question) int {
+	switch {
+	case question.Correct:
+		return 5
+	case question.Points > 0:
+		return 3
+	case question.Answer != "":
+		return 1
+	default:
+		return 0
+	}
+}
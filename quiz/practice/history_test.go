@@ -0,0 +1,105 @@
+package practice
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/roberveral/gophercises/quiz/model"
+	"github.com/roberveral/gophercises/quiz/report"
+)
+
+var now = time.Date(2019, 1, 10, 0, 0, 0, 0, time.UTC)
+
+func questions(quiz *model.Quiz) []string {
+	var questions []string
+	for _, problem := range quiz.Problems {
+		questions = append(questions, problem.Question)
+	}
+	return questions
+}
+
+func TestOrderPracticesDueQuestionsFirst(t *testing.T) {
+	quiz := &model.Quiz{Problems: []model.Problem{
+		model.NewProblem("later", []string{"1"}, nil),
+		model.NewProblem("new", []string{"2"}, nil),
+		model.NewProblem("due", []string{"3"}, nil),
+		model.NewProblem("overdue", []string{"4"}, nil),
+		model.NewProblem("soon", []string{"5"}, nil),
+	}}
+
+	history := NewHistory()
+	history.Cards["later"] = &Card{Due: now.AddDate(0, 0, 5)}
+	history.Cards["due"] = &Card{Due: now}
+	history.Cards["overdue"] = &Card{Due: now.AddDate(0, 0, -3)}
+	history.Cards["soon"] = &Card{Due: now.AddDate(0, 0, 1)}
+
+	history.Order(quiz, now)
+
+	expected := []string{"overdue", "due", "new", "soon", "later"}
+	if !reflect.DeepEqual(questions(quiz), expected) {
+		t.Errorf("Expected order %v, but got %v", expected, questions(quiz))
+	}
+}
+
+func TestRecordSchedulesMissedQuestionsSooner(t *testing.T) {
+	history := NewHistory()
+	history.Cards["5+5"] = &Card{Repetitions: 2, Ease: initialEase, Interval: 6}
+	history.Cards["7+3"] = &Card{Repetitions: 2, Ease: initialEase, Interval: 6}
+
+	history.Record(&report.Report{Questions: []report.Question{
+		{Question: "5+5", Answer: "10", Correct: true},
+		{Question: "7+3", Answer: "11"},
+		{Question: "2+2", Answer: "4", Correct: true},
+	}}, now)
+
+	if due := history.Card("5+5").Due; !due.Equal(now.AddDate(0, 0, 15)) {
+		t.Errorf("Expected correct answer due in 15 days, but got %v", due)
+	}
+	if due := history.Card("7+3").Due; !due.Equal(now.AddDate(0, 0, 1)) {
+		t.Errorf("Expected missed answer due tomorrow, but got %v", due)
+	}
+	if card := history.Card("2+2"); card == nil || card.Repetitions != 1 {
+		t.Errorf("Expected new question to be recorded, but got %+v", card)
+	}
+}
+
+func TestRecordSkipsQuestionsNotAskedBeforeTimeout(t *testing.T) {
+	history := NewHistory()
+	history.Record(&report.Report{TimedOut: true, Questions: []report.Question{
+		{Question: "5+5", Answer: "10", Correct: true},
+		{Question: "7+3", TimedOut: true},
+	}}, now)
+
+	if history.Card("7+3") != nil {
+		t.Errorf("Expected question not asked to be skipped, but got %+v", history.Card("7+3"))
+	}
+}
+
+func TestHistorySaveAndLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history.json")
+
+	empty, err := LoadHistory(path)
+	if err != nil {
+		t.Fatalf("Expected empty history for missing file, but an error was returned: %+v", err)
+	}
+	if len(empty.Cards) != 0 {
+		t.Errorf("Expected empty history, but got %+v", empty.Cards)
+	}
+
+	history := NewHistory()
+	history.Cards["5+5"] = newCard()
+	history.Cards["5+5"].Review(Review{Time: now, Answer: "10", Correct: true, Quality: 5})
+	if err := history.Save(path); err != nil {
+		t.Fatalf("Expected valid result, but an error was returned: %+v", err)
+	}
+
+	loaded, err := LoadHistory(path)
+	if err != nil {
+		t.Fatalf("Expected valid result, but an error was returned: %+v", err)
+	}
+	if !reflect.DeepEqual(loaded, history) {
+		t.Errorf("Expected history %+v, but got %+v", history.Cards["5+5"], loaded.Cards["5+5"])
+	}
+}
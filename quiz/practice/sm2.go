@@ -0,0 +1,82 @@
+package practice
+
+import (
+	"math"
+	"time"
+)
+
+const (
+	// initialEase is the ease factor of the questions which have never been reviewed
+	initialEase = 2.5
+	// minEase is the lowest ease factor, so hard questions are still spaced out
+	minEase = 1.3
+	// day is the unit of the review intervals
+	day = 24 * time.Hour
+)
+
+// Card is the practice record of a question, which schedules when it has to be reviewed
+// with the SM-2 algorithm.
+type Card struct {
+	// Repetitions is the number of consecutive successful reviews
+	Repetitions int `json:"repetitions"`
+	// Ease is the factor by which the interval grows after each successful review
+	Ease float64 `json:"ease"`
+	// Interval is the number of days until the next review
+	Interval int `json:"interval"`
+	// Due is the time when the question has to be reviewed again
+	Due time.Time `json:"due"`
+	// Reviews are all the answers given to the question, oldest first
+	Reviews []Review `json:"reviews,omitempty"`
+}
+
+// Review is an answer given to a question while practicing.
+type Review struct {
+	// Time when the question was answered
+	Time time.Time `json:"time"`
+	// Answer given by the user, empty if unanswered
+	Answer string `json:"answer"`
+	// Correct indicates whether the answer was correct
+	Correct bool `json:"correct"`
+	// Quality of the answer, from 0 (blackout) to 5 (perfect), as defined by SM-2
+	Quality int `json:"quality"`
+}
+
+// newCard creates the Card of a question which has never been reviewed.
+func newCard() *Card {
+	return &Card{Ease: initialEase}
+}
+
+// Review schedules the next review of the question after the given answer, according to its
+// quality and the time it was given. Answers with a quality lower than 3 are lapses which
+// restart the repetitions, so the question comes back the next day.
+func (c *Card) Review(review Review) {
+	quality := review.Quality
+	if quality < 0 {
+		quality = 0
+	} else if quality > 5 {
+		quality = 5
+	}
+
+	if quality < 3 {
+		c.Repetitions = 0
+		c.Interval = 1
+	} else {
+		switch c.Repetitions {
+		case 0:
+			c.Interval = 1
+		case 1:
+			c.Interval = 6
+		default:
+			c.Interval = int(math.Round(float64(c.Interval) * c.Ease))
+		}
+		c.Repetitions++
+	}
+
+	c.Ease += 0.1 - float64(5-quality)*(0.08+float64(5-quality)*0.02)
+	if c.Ease < minEase {
+		c.Ease = minEase
+	}
+
+	c.Due = review.Time.Add(time.Duration(c.Interval) * day)
+	c.Reviews = append(c.Reviews, review)
+}
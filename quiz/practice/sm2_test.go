@@ -0,0 +1,56 @@
+package practice
+
+import (
+	"testing"
+	"time"
+)
+
+func reviewTest(t *testing.T, card *Card, quality int, expectedInterval int) {
+	t.Helper()
+	now := time.Date(2019, 1, 1, 0, 0, 0, 0, time.UTC)
+	card.Review(Review{Time: now, Quality: quality})
+
+	if card.Interval != expectedInterval {
+		t.Errorf("Expected interval of %d days after quality %d, but got %d", expectedInterval, quality, card.Interval)
+	}
+	if expectedDue := now.AddDate(0, 0, expectedInterval); !card.Due.Equal(expectedDue) {
+		t.Errorf("Expected card due at %v, but got %v", expectedDue, card.Due)
+	}
+}
+
+func TestReviewSpacesOutCorrectAnswers(t *testing.T) {
+	card := newCard()
+	reviewTest(t, card, 5, 1)
+	reviewTest(t, card, 5, 6)
+	// Ease grows 0.1 with each perfect answer
+	reviewTest(t, card, 5, 16)
+
+	if card.Repetitions != 3 || len(card.Reviews) != 3 {
+		t.Errorf("Expected 3 repetitions and reviews, but got %d and %d", card.Repetitions, len(card.Reviews))
+	}
+}
+
+func TestReviewRestartsAfterLapse(t *testing.T) {
+	card := newCard()
+	reviewTest(t, card, 5, 1)
+	reviewTest(t, card, 4, 6)
+	reviewTest(t, card, 1, 1)
+
+	if card.Repetitions != 0 {
+		t.Errorf("Expected repetitions to restart after a lapse, but got %d", card.Repetitions)
+	}
+	if card.Ease >= initialEase {
+		t.Errorf("Expected ease to drop after a lapse, but got %v", card.Ease)
+	}
+}
+
+func TestReviewKeepsMinimumEase(t *testing.T) {
+	card := newCard()
+	for i := 0; i < 10; i++ {
+		card.Review(Review{Quality: 0})
+	}
+
+	if card.Ease != minEase {
+		t.Errorf("Expected ease of %v, but got %v", minEase, card.Ease)
+	}
+}
@@ -0,0 +1,200 @@
+package report
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// Report is the detailed outcome of the execution of a quiz, with the answer given
+// by the user to each of the questions.
+type Report struct {
+	// Questions of the quiz, in the order they were asked
+	Questions []Question `json:"questions"`
+	// Correct is the number of correct answers
+	Correct int `json:"correct"`
+	// Total is the number of questions in the quiz
+	Total int `json:"total"`
+	// TimedOut indicates that the time to complete the quiz expired
+	TimedOut bool `json:"timedOut"`
+	// Points scored in the quiz, including partial credit and penalties
+	Points float64 `json:"points"`
+	// MaxPoints is the maximum number of points which can be scored in the quiz
+	MaxPoints float64 `json:"maxPoints"`
+	// PassMark is the percentage of the maximum points needed to pass the quiz, zero if none
+	PassMark float64 `json:"passMark,omitempty"`
+	// Ability of the user estimated as an Elo rating in adaptive quizzes, zero otherwise
+	Ability float64 `json:"ability,omitempty"`
+	// Seed of the random choices made to assemble the quiz, zero if none
+	Seed int64 `json:"seed,omitempty"`
+}
+
+// Question is the outcome of a single question of a quiz.
+type Question struct {
+	// Number of the question in the quiz
+	Number int `json:"number"`
+	// Question asked
+	Question string `json:"question"`
+	// Expected answers to the question
+	Expected []string `json:"expected"`
+	// Answer given by the user, empty if unanswered
+	Answer string `json:"answer"`
+	// Correct indicates whether the answer was correct
+	Correct bool `json:"correct"`
+	// Points scored with the answer, negative if it was penalized
+	Points float64 `json:"points"`
+	// MaxPoints is the number of points the question is worth
+	MaxPoints float64 `json:"maxPoints"`
+	// Elapsed is the time the user took to answer. It is encoded in seconds.
+	Elapsed time.Duration `json:"-"`
+	// TimedOut indicates that the question went unanswered because its time, or the time
+	// of the whole quiz, expired
+	TimedOut bool `json:"timedOut"`
+	// AnsweredAt is the time when the user answered, zero if unanswered
+	AnsweredAt time.Time `json:"answeredAt"`
+	// TooFast indicates that the user answered faster than the minimum time to answer of the
+	// quiz, which is suspicious of cheating
+	TooFast bool `json:"tooFast,omitempty"`
+}
+
+// Percentage returns the points scored as a percentage of the maximum points.
+func (r *Report) Percentage() float64 {
+	if r.MaxPoints == 0 {
+		return 0
+	}
+	return 100 * r.Points / r.MaxPoints
+}
+
+// Passed checks whether the points scored reach the pass mark of the quiz. Quizzes without
+// pass mark are always passed.
+func (r *Report) Passed() bool {
+	return r.PassMark == 0 || r.Percentage() >= r.PassMark
+}
+
+// TooFast returns the number of questions answered faster than the minimum time to answer.
+func (r *Report) TooFast() int {
+	tooFast := 0
+	for _, question := range r.Questions {
+		if question.TooFast {
+			tooFast++
+		}
+	}
+	return tooFast
+}
+
+// MarshalJSON encodes the Report as JSON, including its percentage and whether it was passed.
+func (r Report) MarshalJSON() ([]byte, error) {
+	type plainReport Report // avoids the recursion into MarshalJSON
+	return json.Marshal(struct {
+		plainReport
+		Percentage float64 `json:"percentage"`
+		Passed     bool    `json:"passed"`
+	}{plainReport(r), r.Percentage(), r.Passed()})
+}
+
+// question avoids the recursion into the JSON methods of Question.
+type question Question
+
+// jsonQuestion is the JSON representation of a Question, with the elapsed time in seconds.
+type jsonQuestion struct {
+	question
+	Elapsed float64 `json:"elapsed"`
+}
+
+// MarshalJSON encodes the Question as JSON, with the elapsed time in seconds.
+func (q Question) MarshalJSON() ([]byte, error) {
+	return json.Marshal(jsonQuestion{question(q), q.Elapsed.Seconds()})
+}
+
+// UnmarshalJSON decodes a Question encoded by MarshalJSON.
+func (q *Question) UnmarshalJSON(data []byte) error {
+	var decoded jsonQuestion
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return err
+	}
+
+	*q = Question(decoded.question)
+	q.Elapsed = time.Duration(decoded.Elapsed * float64(time.Second))
+	return nil
+}
+
+// WriteJSON writes the Report as an indented JSON document.
+func (r *Report) WriteJSON(writer io.Writer) error {
+	encoder := json.NewEncoder(writer)
+	encoder.SetIndent("", "  ")
+	return errors.Wrap(encoder.Encode(r), "Unable to write JSON report")
+}
+
+// WriteCSV writes the Report as CSV, with a header and a record for each question.
+// Several expected answers are separated by '|'.
+func (r *Report) WriteCSV(writer io.Writer) error {
+	csvWriter := csv.NewWriter(writer)
+	csvWriter.Write([]string{"number", "question", "expected", "answer", "correct", "points", "maxPoints", "elapsed", "timedOut", "answeredAt", "tooFast"})
+
+	for _, q := range r.Questions {
+		var answeredAt string
+		if !q.AnsweredAt.IsZero() {
+			answeredAt = q.AnsweredAt.Format(time.RFC3339Nano)
+		}
+		csvWriter.Write([]string{
+			strconv.Itoa(q.Number),
+			q.Question,
+			strings.Join(q.Expected, "|"),
+			q.Answer,
+			strconv.FormatBool(q.Correct),
+			strconv.FormatFloat(q.Points, 'f', -1, 64),
+			strconv.FormatFloat(q.MaxPoints, 'f', -1, 64),
+			strconv.FormatFloat(q.Elapsed.Seconds(), 'f', 3, 64),
+			strconv.FormatBool(q.TimedOut),
+			answeredAt,
+			strconv.FormatBool(q.TooFast),
+		})
+	}
+
+	csvWriter.Flush()
+	return errors.Wrap(csvWriter.Error(), "Unable to write CSV report")
+}
+
+// WriteFile writes the Report to the file in the given path, using the format given by
+// its extension: JSON for ".json" and CSV for ".csv".
+func (r *Report) WriteFile(path string) error {
+	write, err := r.writerFor(path)
+	if err != nil {
+		return err
+	}
+
+	file, err := os.Create(path)
+	if err != nil {
+		return errors.Wrap(err, "Unable to create report file")
+	}
+	defer file.Close()
+
+	return write(file)
+}
+
+// CheckPath checks that a report can be written with WriteFile to the given path, so a wrong
+// extension is found before taking the quiz.
+func CheckPath(path string) error {
+	_, err := (&Report{}).writerFor(path)
+	return err
+}
+
+// writerFor returns the method writing the Report in the format given by the extension of the
+// given path.
+func (r *Report) writerFor(path string) (func(io.Writer) error, error) {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		return r.WriteJSON, nil
+	case ".csv":
+		return r.WriteCSV, nil
+	default:
+		return nil, errors.Errorf("Unknown report format for %q, use a .json or .csv file", path)
+	}
+}
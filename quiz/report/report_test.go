@@ -0,0 +1,87 @@
+package report
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func testReport() *Report {
+	return &Report{
+		Questions: []Question{
+			{Number: 0, Question: "5+5", Expected: []string{"10"}, Answer: "10", Correct: true, Points: 1, MaxPoints: 1, Elapsed: 1500 * time.Millisecond,
+				AnsweredAt: time.Date(2019, 1, 1, 10, 0, 1, 500000000, time.UTC), TooFast: true},
+			{Number: 1, Question: "Big Apple?", Expected: []string{"NYC", "New York"}, MaxPoints: 2, Elapsed: 2 * time.Second, TimedOut: true},
+		},
+		Correct:   1,
+		Total:     2,
+		Points:    1,
+		MaxPoints: 3,
+		PassMark:  50,
+		Seed:      42,
+	}
+}
+
+func TestReportJSONRoundTrip(t *testing.T) {
+	var buffer bytes.Buffer
+	if err := testReport().WriteJSON(&buffer); err != nil {
+		t.Fatalf("Expected valid result, but an error was returned: %+v", err)
+	}
+
+	var decoded Report
+	if err := json.Unmarshal(buffer.Bytes(), &decoded); err != nil {
+		t.Fatalf("Expected valid JSON, but an error was returned: %+v", err)
+	}
+
+	if !reflect.DeepEqual(&decoded, testReport()) {
+		t.Errorf("Expected report %+v, but got %+v", testReport(), decoded)
+	}
+}
+
+func TestReportCSV(t *testing.T) {
+	expected := `number,question,expected,answer,correct,points,maxPoints,elapsed,timedOut,answeredAt,tooFast
+0,5+5,10,10,true,1,1,1.500,false,2019-01-01T10:00:01.5Z,true
+1,Big Apple?,NYC|New York,,false,0,2,2.000,true,,false
+`
+
+	var buffer bytes.Buffer
+	if err := testReport().WriteCSV(&buffer); err != nil {
+		t.Fatalf("Expected valid result, but an error was returned: %+v", err)
+	}
+
+	if buffer.String() != expected {
+		t.Errorf("Expected CSV:\n%s\nbut got:\n%s", expected, buffer.String())
+	}
+}
+
+func TestCheckPath(t *testing.T) {
+	for path, valid := range map[string]bool{"report.json": true, "report.CSV": true, "report.txt": false, "report": false} {
+		if err := CheckPath(path); (err == nil) != valid {
+			t.Errorf("Expected %q to be valid %v, but got error %v", path, valid, err)
+		}
+	}
+}
+
+func TestReportPassMark(t *testing.T) {
+	quizReport := testReport()
+
+	if percentage := quizReport.Percentage(); percentage < 33.3 || percentage > 33.4 {
+		t.Errorf("Expected a percentage of 33.3, but got %v", percentage)
+	}
+	if quizReport.Passed() {
+		t.Error("Expected the quiz to be failed below the pass mark")
+	}
+
+	quizReport.PassMark = 0
+	if !quizReport.Passed() {
+		t.Error("Expected the quiz to be passed without pass mark")
+	}
+
+	// Penalties can make the points negative
+	quizReport.Points = -1
+	if !quizReport.Passed() {
+		t.Error("Expected the quiz to be passed with negative points without pass mark")
+	}
+}
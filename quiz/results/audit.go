@@ -0,0 +1,84 @@
+package results
+
+import (
+	"encoding/json"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/roberveral/gophercises/quiz/report"
+)
+
+// AuditEntry is the record of an answer in the audit log.
+type AuditEntry struct {
+	// Time when the answer was given
+	Time time.Time `json:"time"`
+	// User who answered
+	User string `json:"user"`
+	// Quiz taken, identified by its title or its file
+	Quiz string `json:"quiz"`
+	// Number of the question in the quiz
+	Number int `json:"number"`
+	// Question answered
+	Question string `json:"question"`
+	// Answer given by the user
+	Answer string `json:"answer"`
+	// Correct indicates whether the answer was correct
+	Correct bool `json:"correct"`
+	// Elapsed is the time in seconds the user took to answer
+	Elapsed float64 `json:"elapsed"`
+	// TooFast indicates that the answer was suspiciously fast
+	TooFast bool `json:"tooFast,omitempty"`
+}
+
+// AuditLog is an append-only file with a JSON line for each answer given, so the answers of
+// the quizzes taken remotely can be reviewed. It can be shared by several goroutines.
+type AuditLog struct {
+	path  string
+	mutex sync.Mutex
+}
+
+// NewAuditLog creates an AuditLog which keeps the answers in the file in the given path. The
+// file is created with the first answer recorded.
+func NewAuditLog(path string) *AuditLog {
+	return &AuditLog{path: path}
+}
+
+// Record appends an entry for the given question answered by the given user in the given
+// quiz. Unanswered questions aren't recorded.
+func (l *AuditLog) Record(user, quiz string, question report.Question) error {
+	if question.AnsweredAt.IsZero() {
+		return nil
+	}
+
+	line, err := json.Marshal(AuditEntry{
+		Time:     question.AnsweredAt,
+		User:     user,
+		Quiz:     quiz,
+		Number:   question.Number,
+		Question: question.Question,
+		Answer:   question.Answer,
+		Correct:  question.Correct,
+		Elapsed:  question.Elapsed.Seconds(),
+		TooFast:  question.TooFast,
+	})
+	if err != nil {
+		return errors.Wrap(err, "Unable to encode audit entry")
+	}
+
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	file, err := os.OpenFile(l.path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0644)
+	if err != nil {
+		return errors.Wrap(err, "Unable to open audit log")
+	}
+	defer file.Close()
+
+	if _, err := file.Write(append(line, '\n')); err != nil {
+		return errors.Wrap(err, "Unable to write to audit log")
+	}
+	return errors.Wrap(file.Close(), "Unable to write to audit log")
+}
@@ -0,0 +1,49 @@
+package results
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/roberveral/gophercises/quiz/report"
+)
+
+func TestAuditLogRecordsTheAnswers(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+	answeredAt := time.Date(2019, 1, 1, 10, 0, 1, 0, time.UTC)
+	questions := []report.Question{
+		{Number: 0, Question: "5+5", Answer: "10", Correct: true, Elapsed: 200 * time.Millisecond, AnsweredAt: answeredAt, TooFast: true},
+		{Number: 1, Question: "1+1", TimedOut: true},
+	}
+
+	auditLog := NewAuditLog(path)
+	for _, question := range questions {
+		if err := auditLog.Record("alice", "sums", question); err != nil {
+			t.Fatalf("Expected valid result, but an error was returned: %+v", err)
+		}
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("Expected the audit log to be created, but an error was returned: %+v", err)
+	}
+	defer file.Close()
+
+	var entries []AuditEntry
+	for scanner := bufio.NewScanner(file); scanner.Scan(); {
+		var entry AuditEntry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			t.Fatalf("Expected valid entry, but an error was returned: %+v", err)
+		}
+		entries = append(entries, entry)
+	}
+
+	expected := []AuditEntry{{Time: answeredAt, User: "alice", Quiz: "sums", Number: 0, Question: "5+5", Answer: "10", Correct: true, Elapsed: 0.2, TooFast: true}}
+	if !reflect.DeepEqual(entries, expected) {
+		t.Errorf("Expected entries %+v, but got %+v", expected, entries)
+	}
+}
@@ -0,0 +1,134 @@
+package results
+
+import (
+	"encoding/csv"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Grading is the way a user is graded in a quiz taken several times, as the grading methods of
+// the quizzes of LMSs like Moodle.
+type Grading int
+
+const (
+	// Highest grades the user with the best run
+	Highest Grading = iota
+	// Average grades the user with the average of all the runs
+	Average
+	// First grades the user with the first run
+	First
+	// Last grades the user with the last run
+	Last
+)
+
+var gradingNames = []string{"highest", "average", "first", "last"}
+
+// ParseGrading parses the name of a Grading: "highest", "average", "first" or "last".
+func ParseGrading(name string) (Grading, error) {
+	for grading, gradingName := range gradingNames {
+		if strings.EqualFold(strings.TrimSpace(name), gradingName) {
+			return Grading(grading), nil
+		}
+	}
+	return Highest, errors.Errorf("Unknown grading %q, expected any of %v", name, gradingNames)
+}
+
+// String returns the name of the Grading, as understood by ParseGrading.
+func (g Grading) String() string {
+	if int(g) < len(gradingNames) {
+		return gradingNames[g]
+	}
+	return "Grading(" + strconv.Itoa(int(g)) + ")"
+}
+
+// Gradebook is the grade of each user in each quiz, as the percentage of the points scored.
+type Gradebook struct {
+	// Users graded, sorted by name
+	Users []string
+	// Quizzes graded, sorted by name
+	Quizzes []string
+	// Grades of each user in each quiz taken, by user and quiz
+	Grades map[string]map[string]float64
+}
+
+// NewGradebook grades the users in the quizzes of the given runs, in the order they were
+// completed, with the given Grading.
+func NewGradebook(runs []Run, grading Grading) *Gradebook {
+	percentages := make(map[string]map[string][]float64)
+	quizzes := make(map[string]bool)
+	for _, run := range runs {
+		if percentages[run.User] == nil {
+			percentages[run.User] = make(map[string][]float64)
+		}
+		percentages[run.User][run.Quiz] = append(percentages[run.User][run.Quiz], run.Report.Percentage())
+		quizzes[run.Quiz] = true
+	}
+
+	gradebook := &Gradebook{Grades: make(map[string]map[string]float64)}
+	for user, userPercentages := range percentages {
+		gradebook.Users = append(gradebook.Users, user)
+		gradebook.Grades[user] = make(map[string]float64)
+		for quiz, quizPercentages := range userPercentages {
+			gradebook.Grades[user][quiz] = grading.grade(quizPercentages)
+		}
+	}
+	for quiz := range quizzes {
+		gradebook.Quizzes = append(gradebook.Quizzes, quiz)
+	}
+	sort.Strings(gradebook.Users)
+	sort.Strings(gradebook.Quizzes)
+
+	return gradebook
+}
+
+// grade returns the grade for the given percentages of the runs, oldest first.
+func (g Grading) grade(percentages []float64) float64 {
+	switch g {
+	case Average:
+		sum := 0.0
+		for _, percentage := range percentages {
+			sum += percentage
+		}
+		return sum / float64(len(percentages))
+	case First:
+		return percentages[0]
+	case Last:
+		return percentages[len(percentages)-1]
+	default:
+		highest := percentages[0]
+		for _, percentage := range percentages[1:] {
+			if percentage > highest {
+				highest = percentage
+			}
+		}
+		return highest
+	}
+}
+
+// WriteCSV writes the gradebook as CSV, with a row for each user and a column for each quiz after
+// the "username" column, as imported by the gradebooks of LMSs like Moodle or Canvas. Grades are
+// percentages with two decimals, left empty for the quizzes the user didn't take.
+func (g *Gradebook) WriteCSV(writer io.Writer) error {
+	csvWriter := csv.NewWriter(writer)
+	csvWriter.Write(append([]string{"username"}, g.Quizzes...))
+
+	for _, user := range g.Users {
+		record := []string{user}
+		for _, quiz := range g.Quizzes {
+			grade, ok := g.Grades[user][quiz]
+			if !ok {
+				record = append(record, "")
+				continue
+			}
+			record = append(record, strconv.FormatFloat(grade, 'f', 2, 64))
+		}
+		csvWriter.Write(record)
+	}
+
+	csvWriter.Flush()
+	return errors.Wrap(csvWriter.Error(), "Unable to write gradebook")
+}
@@ -0,0 +1,44 @@
+package results
+
+import (
+	"bytes"
+	"testing"
+)
+
+func gradebookRuns() []Run {
+	capitals := run("alice", 100)
+	capitals.Quiz = "capitals"
+	return []Run{run("bob", 50), run("alice", 20), capitals, run("bob", 75), run("bob", 40)}
+}
+
+func TestGradebookGrading(t *testing.T) {
+	expected := map[Grading]float64{Highest: 75, Average: 55, First: 50, Last: 40}
+
+	for grading, grade := range expected {
+		gradebook := NewGradebook(gradebookRuns(), grading)
+		if actual := gradebook.Grades["bob"]["sums"]; actual != grade {
+			t.Errorf("Expected %s grade %v, but got %v", grading, grade, actual)
+		}
+	}
+}
+
+func TestGradebookWriteCSV(t *testing.T) {
+	var buffer bytes.Buffer
+	if err := NewGradebook(gradebookRuns(), Highest).WriteCSV(&buffer); err != nil {
+		t.Fatalf("Expected gradebook to be written, but an error was returned: %+v", err)
+	}
+
+	expected := "username,capitals,sums\nalice,100.00,20.00\nbob,,75.00\n"
+	if buffer.String() != expected {
+		t.Errorf("Expected gradebook %q, but got %q", expected, buffer.String())
+	}
+}
+
+func TestParseGrading(t *testing.T) {
+	if grading, err := ParseGrading(" Last "); err != nil || grading != Last {
+		t.Errorf("Expected last grading, but got %v (%v)", grading, err)
+	}
+	if _, err := ParseGrading("best"); err == nil {
+		t.Error("Expected an error for an unknown grading, but got none")
+	}
+}
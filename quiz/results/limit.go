@@ -0,0 +1,39 @@
+package results
+
+import (
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// RetakeLimit limits the number of times each user can take a quiz within a period of time.
+type RetakeLimit struct {
+	// Max is the number of runs allowed, unlimited if zero
+	Max int
+	// Window is the period of time in which the runs are counted, all the runs if zero
+	Window time.Duration
+}
+
+// Check returns an error if the user already took the quiz the maximum number of times within
+// the window before the given time, according to the given runs. The given number of pending
+// attempts, which are still in progress and not recorded yet, count as taken.
+func (l RetakeLimit) Check(runs []Run, pending int, user, quiz string, now time.Time) error {
+	if l.Max <= 0 {
+		return nil
+	}
+
+	attempts := pending
+	for _, run := range Filter(runs, user, quiz) {
+		if l.Window == 0 || run.Time.After(now.Add(-l.Window)) {
+			attempts++
+		}
+	}
+	if attempts < l.Max {
+		return nil
+	}
+
+	if l.Window == 0 {
+		return errors.Errorf("%s reached the maximum of %d attempts at %q", user, l.Max, quiz)
+	}
+	return errors.Errorf("%s reached the maximum of %d attempts at %q in the last %v", user, l.Max, quiz, l.Window)
+}
@@ -0,0 +1,30 @@
+package results
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRetakeLimitCountsTheRunsInTheWindow(t *testing.T) {
+	now := time.Date(2019, 1, 2, 10, 0, 0, 0, time.UTC)
+	runs := []Run{
+		{Time: now.Add(-48 * time.Hour), User: "alice", Quiz: "sums"},
+		{Time: now.Add(-time.Hour), User: "alice", Quiz: "sums"},
+		{Time: now.Add(-time.Hour), User: "alice", Quiz: "capitals"},
+		{Time: now.Add(-time.Hour), User: "bob", Quiz: "sums"},
+	}
+	limit := RetakeLimit{Max: 2, Window: 24 * time.Hour}
+
+	if err := limit.Check(runs, 0, "alice", "sums", now); err != nil {
+		t.Errorf("Expected alice to retake the quiz once more, but got %v", err)
+	}
+	if err := limit.Check(append(runs, Run{Time: now, User: "alice", Quiz: "sums"}), 0, "alice", "sums", now); err == nil {
+		t.Error("Expected alice to run out of retakes")
+	}
+	if err := (RetakeLimit{Max: 2}).Check(runs, 0, "alice", "sums", now); err == nil {
+		t.Error("Expected all the runs to count without window")
+	}
+	if err := limit.Check(runs, 1, "alice", "sums", now); err == nil {
+		t.Error("Expected the pending attempt of alice to count")
+	}
+}
@@ -0,0 +1,171 @@
+package results
+
+import (
+	"sort"
+	"time"
+)
+
+// Stats are the statistics of a set of runs.
+type Stats struct {
+	// Runs is the number of runs
+	Runs int
+	// Users are the statistics of each user, sorted by name
+	Users []UserStats
+	// Questions are the statistics of each question, the hardest first
+	Questions []QuestionStats
+}
+
+// UserStats are the statistics of the runs of a user.
+type UserStats struct {
+	// User the statistics belong to
+	User string
+	// Runs is the number of runs of the user
+	Runs int
+	// Correct is the number of correct answers in all the runs
+	Correct int
+	// Total is the number of questions in all the runs
+	Total int
+	// Answered is the number of questions which were asked to the user in all the runs
+	Answered int
+	// Elapsed is the time the user took to answer the questions asked
+	Elapsed time.Duration
+	// Percentages are the percentages of points scored in each run, oldest first
+	Percentages []float64
+}
+
+// QuestionStats are the statistics of the answers to a question.
+type QuestionStats struct {
+	// Question asked
+	Question string
+	// Asked is the number of times the question was asked
+	Asked int
+	// Correct is the number of times the question was answered correctly
+	Correct int
+	// Elapsed is the time taken to answer the question, in all the runs
+	Elapsed time.Duration
+}
+
+// Compute computes the statistics of the given runs, in the order they were completed.
+func Compute(runs []Run) *Stats {
+	users := make(map[string]*UserStats)
+	questions := make(map[string]*QuestionStats)
+
+	for _, run := range runs {
+		user := users[run.User]
+		if user == nil {
+			user = &UserStats{User: run.User}
+			users[run.User] = user
+		}
+		user.Runs++
+		user.Correct += run.Report.Correct
+		user.Total += run.Report.Total
+		user.Percentages = append(user.Percentages, run.Report.Percentage())
+
+		for _, answer := range run.Report.Questions {
+			// Questions left when the time of the quiz expired weren't asked
+			if answer.Elapsed == 0 && answer.Answer == "" {
+				continue
+			}
+			user.Answered++
+			user.Elapsed += answer.Elapsed
+
+			question := questions[answer.Question]
+			if question == nil {
+				question = &QuestionStats{Question: answer.Question}
+				questions[answer.Question] = question
+			}
+			question.Asked++
+			question.Elapsed += answer.Elapsed
+			if answer.Correct {
+				question.Correct++
+			}
+		}
+	}
+
+	stats := &Stats{Runs: len(runs)}
+	for _, user := range users {
+		stats.Users = append(stats.Users, *user)
+	}
+	sort.Slice(stats.Users, func(i, j int) bool { return stats.Users[i].User < stats.Users[j].User })
+
+	for _, question := range questions {
+		stats.Questions = append(stats.Questions, *question)
+	}
+	sort.Slice(stats.Questions, func(i, j int) bool {
+		a, b := stats.Questions[i], stats.Questions[j]
+		if a.Accuracy() != b.Accuracy() {
+			return a.Accuracy() < b.Accuracy()
+		}
+		if a.Asked != b.Asked {
+			return a.Asked > b.Asked
+		}
+		return a.Question < b.Question
+	})
+
+	return stats
+}
+
+// Accuracy returns the percentage of correct answers of the user.
+func (u UserStats) Accuracy() float64 {
+	return percentage(u.Correct, u.Total)
+}
+
+// AverageTime returns the average time the user took to answer a question.
+func (u UserStats) AverageTime() time.Duration {
+	return average(u.Elapsed, u.Answered)
+}
+
+// Trend returns how many percentage points the score of the user improves in each run, as
+// the slope of the least squares line of the percentages of the runs. It is zero with less
+// than two runs.
+func (u UserStats) Trend() float64 {
+	n := float64(len(u.Percentages))
+	if n < 2 {
+		return 0
+	}
+
+	var sumX, sumY, sumXY, sumXX float64
+	for i, y := range u.Percentages {
+		x := float64(i)
+		sumX += x
+		sumY += y
+		sumXY += x * y
+		sumXX += x * x
+	}
+	return (n*sumXY - sumX*sumY) / (n*sumXX - sumX*sumX)
+}
+
+// Accuracy returns the percentage of times the question was answered correctly.
+func (q QuestionStats) Accuracy() float64 {
+	return percentage(q.Correct, q.Asked)
+}
+
+// AverageTime returns the average time taken to answer the question.
+func (q QuestionStats) AverageTime() time.Duration {
+	return average(q.Elapsed, q.Asked)
+}
+
+func percentage(part, total int) float64 {
+	if total == 0 {
+		return 0
+	}
+	return 100 * float64(part) / float64(total)
+}
+
+func average(total time.Duration, count int) time.Duration {
+	if count == 0 {
+		return 0
+	}
+	return total / time.Duration(count)
+}
+
+// Filter returns the runs of the given user and quiz. Empty values don't filter.
+func Filter(runs []Run, user, quiz string) []Run {
+	var filtered []Run
+	for _, run := range runs {
+		if (user == "" || run.User == user) && (quiz == "" || run.Quiz == quiz) {
+			filtered = append(filtered, run)
+		}
+	}
+	return filtered
+}
@@ -0,0 +1,59 @@
+package results
+
+import (
+	"testing"
+	"time"
+
+	"github.com/roberveral/gophercises/quiz/report"
+)
+
+func run(user string, percentage float64, answers ...report.Question) Run {
+	quizReport := &report.Report{Questions: answers, Total: len(answers), Points: percentage, MaxPoints: 100}
+	for _, answer := range answers {
+		if answer.Correct {
+			quizReport.Correct++
+		}
+	}
+	return Run{User: user, Quiz: "sums", Report: quizReport}
+}
+
+func answer(question string, correct bool, elapsed time.Duration) report.Question {
+	return report.Question{Question: question, Answer: "x", Correct: correct, Elapsed: elapsed}
+}
+
+func TestCompute(t *testing.T) {
+	stats := Compute([]Run{
+		run("bob", 50, answer("5+5", true, time.Second), answer("7*8", false, 3*time.Second)),
+		run("alice", 0, answer("5+5", false, 2*time.Second), report.Question{Question: "7*8", TimedOut: true}),
+		run("bob", 100, answer("5+5", true, time.Second), answer("7*8", true, 5*time.Second)),
+	})
+
+	if stats.Runs != 3 || len(stats.Users) != 2 || len(stats.Questions) != 2 {
+		t.Fatalf("Expected 3 runs of 2 users and 2 questions, but got %+v", stats)
+	}
+
+	alice, bob := stats.Users[0], stats.Users[1]
+	if alice.User != "alice" || alice.Accuracy() != 0 || alice.AverageTime() != 2*time.Second {
+		t.Errorf("Expected alice with no correct answers in 2s, but got %+v", alice)
+	}
+	if bob.Accuracy() != 75 || bob.AverageTime() != 2500*time.Millisecond || bob.Trend() != 50 {
+		t.Errorf("Expected bob with 75%% accuracy in 2.5s improving 50 points, but got %+v", bob)
+	}
+
+	// The question not reached by alice doesn't count
+	hardest := stats.Questions[0]
+	if hardest.Question != "7*8" || hardest.Asked != 2 || hardest.Accuracy() != 50 || hardest.AverageTime() != 4*time.Second {
+		t.Errorf("Expected 7*8 as the hardest question, but got %+v", hardest)
+	}
+}
+
+func TestFilter(t *testing.T) {
+	runs := []Run{run("alice", 0), run("bob", 0), {User: "alice", Quiz: "capitals"}}
+
+	if filtered := Filter(runs, "alice", ""); len(filtered) != 2 {
+		t.Errorf("Expected 2 runs of alice, but got %+v", filtered)
+	}
+	if filtered := Filter(runs, "alice", "capitals"); len(filtered) != 1 {
+		t.Errorf("Expected 1 run of alice in capitals, but got %+v", filtered)
+	}
+}
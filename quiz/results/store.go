@@ -0,0 +1,93 @@
+// Package results stores the results of the quizzes taken, so the progress of the users can
+// be followed over time.
+package results
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/roberveral/gophercises/quiz/report"
+)
+
+// Run is a completed run of a quiz.
+type Run struct {
+	// Time when the quiz was completed
+	Time time.Time `json:"time"`
+	// User who took the quiz
+	User string `json:"user"`
+	// Quiz taken, identified by its title or its file
+	Quiz string `json:"quiz"`
+	// Report of the answers given by the user
+	Report *report.Report `json:"report"`
+}
+
+// Store is an append-only file with a JSON line for each completed run. It can be
+// shared by several goroutines recording runs at once.
+type Store struct {
+	path  string
+	mutex sync.Mutex
+}
+
+// NewStore creates a Store which keeps the runs in the file in the given path. The file
+// is created with the first run recorded.
+func NewStore(path string) *Store {
+	return &Store{path: path}
+}
+
+// Record appends the given run to the store.
+func (s *Store) Record(run Run) error {
+	line, err := json.Marshal(run)
+	if err != nil {
+		return errors.Wrap(err, "Unable to encode run")
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	file, err := os.OpenFile(s.path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0644)
+	if err != nil {
+		return errors.Wrap(err, "Unable to open results store")
+	}
+	defer file.Close()
+
+	if _, err := file.Write(append(line, '\n')); err != nil {
+		return errors.Wrap(err, "Unable to write to results store")
+	}
+	return errors.Wrap(file.Close(), "Unable to write to results store")
+}
+
+// Runs reads all the runs in the store, oldest first. A missing file is an empty store.
+func (s *Store) Runs() ([]Run, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	file, err := os.Open(s.path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "Unable to open results store")
+	}
+	defer file.Close()
+
+	var runs []Run
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(nil, 16*1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+
+		var run Run
+		if err := json.Unmarshal(scanner.Bytes(), &run); err != nil || run.Report == nil {
+			return nil, errors.Errorf("Malformed results store, line %d", line)
+		}
+		runs = append(runs, run)
+	}
+	return runs, errors.Wrap(scanner.Err(), "Unable to read results store")
+}
@@ -0,0 +1,47 @@
+package results
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/roberveral/gophercises/quiz/report"
+)
+
+func TestStoreRecordsRuns(t *testing.T) {
+	store := NewStore(filepath.Join(t.TempDir(), "results.jsonl"))
+
+	runs, err := store.Runs()
+	if err != nil || len(runs) != 0 {
+		t.Fatalf("Expected empty store, but got %v and %v", runs, err)
+	}
+
+	recorded := []Run{
+		{Time: time.Date(2019, 1, 1, 10, 0, 0, 0, time.UTC), User: "alice", Quiz: "sums", Report: &report.Report{Correct: 1, Total: 2}},
+		{Time: time.Date(2019, 1, 2, 10, 0, 0, 0, time.UTC), User: "bob", Quiz: "sums", Report: &report.Report{Correct: 2, Total: 2}},
+	}
+	for _, run := range recorded {
+		if err := store.Record(run); err != nil {
+			t.Fatalf("Expected valid result, but an error was returned: %+v", err)
+		}
+	}
+
+	runs, err = store.Runs()
+	if err != nil {
+		t.Fatalf("Expected valid result, but an error was returned: %+v", err)
+	}
+	if !reflect.DeepEqual(runs, recorded) {
+		t.Errorf("Expected runs %+v, but got %+v", recorded, runs)
+	}
+}
+
+func TestStoreReportsMalformedLines(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "results.jsonl")
+	os.WriteFile(path, []byte("{\"user\":\"alice\",\"report\":{}}\n{oops\n"), 0644)
+
+	if _, err := NewStore(path).Runs(); err == nil || err.Error() != "Malformed results store, line 2" {
+		t.Errorf("Expected malformed line 2, but got %v", err)
+	}
+}
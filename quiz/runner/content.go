@@ -0,0 +1,280 @@
+package runner
+
+import (
+	"fmt"
+	"html"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// Markdown syntax understood when rendering questions. The inline markup is matched after
+// escaping the HTML of the text.
+var (
+	markdownImage    = regexp.MustCompile(`!\[([^\]]*)\]\(([^)\s]+)\)`)
+	markdownLink     = regexp.MustCompile(`\[([^\]]+)\]\(([^)\s]+)\)`)
+	markdownStrong   = regexp.MustCompile(`\*\*([^*]+)\*\*|__([^_]+)__`)
+	markdownEmphasis = regexp.MustCompile(`\*([^*]+)\*|\b_([^_]+)_\b`)
+	markdownHeading  = regexp.MustCompile(`^(#{1,6})\s+(.*)$`)
+	markdownItem     = regexp.MustCompile(`^\s*(?:[-*+]|\d+\.)\s+(.*)$`)
+	markdownOrdered  = regexp.MustCompile(`^\s*\d+\.\s`)
+)
+
+// text returns the text of the question as plain text, without the Markdown markup.
+func (q Question) text() string {
+	if !q.Markdown {
+		return q.Text
+	}
+
+	lines := strings.Split(q.Text, "\n")
+	for i, line := range lines {
+		if heading := markdownHeading.FindStringSubmatch(line); heading != nil {
+			line = heading[2]
+		}
+		line = markdownImage.ReplaceAllString(line, "[Image: $2]")
+		line = markdownLink.ReplaceAllString(line, "$1 ($2)")
+		line = markdownStrong.ReplaceAllString(line, "$1$2")
+		line = markdownEmphasis.ReplaceAllString(line, "$1$2")
+		lines[i] = strings.Replace(line, "`", "", -1)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// attachments returns the text fallback of the code snippet and the image of the question, if
+// any: the code indented and the path of the image.
+func (q Question) attachments() string {
+	var attachments strings.Builder
+	if code := strings.Trim(q.Code, "\n"); code != "" {
+		for _, line := range strings.Split(code, "\n") {
+			fmt.Fprintf(&attachments, "    %s\n", line)
+		}
+	}
+	if q.Image != "" {
+		fmt.Fprintf(&attachments, "  [Image: %s]\n", q.Image)
+	}
+	return attachments.String()
+}
+
+// renderHTML renders the question as HTML: its text, from Markdown if it is written in it,
+// its code snippet with the syntax highlighted and its image. The sources of the images are
+// given by the media function, so local files can be served.
+func renderHTML(q Question, media func(path string) string) string {
+	var rendered strings.Builder
+	if q.Markdown {
+		rendered.WriteString(renderMarkdown(q.Text, media))
+	} else {
+		fmt.Fprintf(&rendered, "<p>%s</p>", html.EscapeString(q.Text))
+	}
+
+	if code := strings.Trim(q.Code, "\n"); code != "" {
+		rendered.WriteString(renderCode(code, q.Language))
+	}
+	if q.Image != "" {
+		fmt.Fprintf(&rendered, `<img src="%s" alt="">`, html.EscapeString(media(q.Image)))
+	}
+	return rendered.String()
+}
+
+// renderMarkdown renders a subset of Markdown as HTML: paragraphs, headings, lists, fenced code
+// blocks, and inline code, emphasis, links and images.
+func renderMarkdown(markdown string, media func(path string) string) string {
+	var rendered strings.Builder
+	var paragraph, items []string
+	ordered := false
+
+	flush := func() {
+		if len(paragraph) > 0 {
+			fmt.Fprintf(&rendered, "<p>%s</p>", renderInline(strings.Join(paragraph, "\n"), media))
+			paragraph = nil
+		}
+		if len(items) > 0 {
+			list := "ul"
+			if ordered {
+				list = "ol"
+			}
+			fmt.Fprintf(&rendered, "<%s>", list)
+			for _, item := range items {
+				fmt.Fprintf(&rendered, "<li>%s</li>", renderInline(item, media))
+			}
+			fmt.Fprintf(&rendered, "</%s>", list)
+			items = nil
+		}
+	}
+
+	lines := strings.Split(markdown, "\n")
+	for i := 0; i < len(lines); i++ {
+		line := lines[i]
+		switch {
+		case strings.HasPrefix(strings.TrimSpace(line), "```"):
+			flush()
+			language := strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(line), "```"))
+			var code []string
+			for i++; i < len(lines) && !strings.HasPrefix(strings.TrimSpace(lines[i]), "```"); i++ {
+				code = append(code, lines[i])
+			}
+			rendered.WriteString(renderCode(strings.Join(code, "\n"), language))
+		case strings.TrimSpace(line) == "":
+			flush()
+		case markdownHeading.MatchString(line):
+			flush()
+			heading := markdownHeading.FindStringSubmatch(line)
+			level := len(heading[1]) + 2
+			if level > 6 {
+				level = 6
+			}
+			fmt.Fprintf(&rendered, "<h%d>%s</h%d>", level, renderInline(heading[2], media), level)
+		case markdownItem.MatchString(line):
+			if len(paragraph) > 0 || (len(items) > 0 && ordered != markdownOrdered.MatchString(line)) {
+				flush()
+			}
+			ordered = markdownOrdered.MatchString(line)
+			items = append(items, markdownItem.FindStringSubmatch(line)[1])
+		default:
+			if len(items) > 0 {
+				flush()
+			}
+			paragraph = append(paragraph, strings.TrimSpace(line))
+		}
+	}
+	flush()
+	return rendered.String()
+}
+
+// renderInline renders the inline Markdown markup of a text as HTML. The markup isn't
+// rendered inside inline code.
+func renderInline(text string, media func(path string) string) string {
+	var rendered strings.Builder
+	for i, part := range strings.Split(text, "`") {
+		escaped := html.EscapeString(part)
+		if i%2 == 1 {
+			fmt.Fprintf(&rendered, "<code>%s</code>", escaped)
+			continue
+		}
+
+		escaped = markdownImage.ReplaceAllStringFunc(escaped, func(image string) string {
+			match := markdownImage.FindStringSubmatch(image)
+			source := html.EscapeString(media(html.UnescapeString(match[2])))
+			return fmt.Sprintf(`<img src="%s" alt="%s">`, source, match[1])
+		})
+		escaped = markdownLink.ReplaceAllStringFunc(escaped, func(link string) string {
+			match := markdownLink.FindStringSubmatch(link)
+			if !safeURL(html.UnescapeString(match[2])) {
+				return match[1]
+			}
+			return fmt.Sprintf(`<a href="%s" target="_blank">%s</a>`, match[2], match[1])
+		})
+		escaped = markdownStrong.ReplaceAllString(escaped, "<strong>$1$2</strong>")
+		escaped = markdownEmphasis.ReplaceAllString(escaped, "<em>$1$2</em>")
+		rendered.WriteString(strings.Replace(escaped, "\n", "<br>", -1))
+	}
+	return rendered.String()
+}
+
+// safeURL checks whether a link can be followed safely: either it is relative or it is a web URL.
+func safeURL(url string) bool {
+	scheme := strings.ToLower(url)
+	if i := strings.IndexAny(scheme, ":/?#"); i < 0 || scheme[i] != ':' {
+		return true
+	}
+	return strings.HasPrefix(scheme, "http:") || strings.HasPrefix(scheme, "https:") || strings.HasPrefix(scheme, "mailto:")
+}
+
+// keywords of the languages whose syntax can be highlighted.
+var keywords = map[string][]string{
+	"go": {"break", "case", "chan", "const", "continue", "default", "defer", "else", "fallthrough", "for", "func", "go",
+		"goto", "if", "import", "interface", "map", "package", "range", "return", "select", "struct", "switch", "type", "var",
+		"nil", "true", "false"},
+	"python": {"and", "as", "assert", "break", "class", "continue", "def", "del", "elif", "else", "except", "finally", "for",
+		"from", "global", "if", "import", "in", "is", "lambda", "not", "or", "pass", "raise", "return", "try", "while",
+		"with", "yield", "None", "True", "False"},
+	"javascript": {"async", "await", "break", "case", "catch", "class", "const", "continue", "default", "delete", "do",
+		"else", "export", "for", "function", "if", "import", "in", "instanceof", "let", "new", "return", "switch", "this",
+		"throw", "try", "typeof", "var", "while", "null", "undefined", "true", "false"},
+	"java": {"abstract", "boolean", "break", "case", "catch", "class", "continue", "default", "do", "double", "else",
+		"extends", "final", "for", "if", "implements", "import", "int", "interface", "long", "new", "package", "private",
+		"protected", "public", "return", "static", "switch", "this", "throw", "throws", "try", "void", "while", "null",
+		"true", "false"},
+	"c": {"break", "case", "char", "const", "continue", "default", "do", "double", "else", "enum", "float", "for", "if",
+		"int", "long", "return", "short", "signed", "sizeof", "static", "struct", "switch", "typedef", "unsigned", "void",
+		"while", "NULL"},
+	"shell": {"case", "do", "done", "elif", "else", "esac", "export", "fi", "for", "function", "if", "in", "local",
+		"return", "then", "while"},
+}
+
+// languageAliases are other names of the languages whose syntax can be highlighted.
+var languageAliases = map[string]string{
+	"golang": "go", "py": "python", "js": "javascript", "ts": "javascript", "typescript": "javascript",
+	"cpp": "c", "c++": "c", "sh": "shell", "bash": "shell",
+}
+
+// renderCode renders a code snippet as HTML, highlighting the keywords, strings, numbers and
+// comments when the language is one of the known ones.
+func renderCode(code, language string) string {
+	language = strings.ToLower(language)
+	if alias, ok := languageAliases[language]; ok {
+		language = alias
+	}
+	if _, ok := keywords[language]; !ok {
+		return fmt.Sprintf("<pre><code>%s</code></pre>", html.EscapeString(code))
+	}
+	isKeyword := make(map[string]bool)
+	for _, keyword := range keywords[language] {
+		isKeyword[keyword] = true
+	}
+	hashComments := language == "python" || language == "shell"
+
+	var rendered strings.Builder
+	span := func(class, token string) {
+		fmt.Fprintf(&rendered, `<span class="%s">%s</span>`, class, html.EscapeString(token))
+	}
+
+	runes := []rune(code)
+	for i := 0; i < len(runes); {
+		start := i
+		switch r := runes[i]; {
+		case r == '/' && i+1 < len(runes) && runes[i+1] == '/', r == '#' && hashComments:
+			for i < len(runes) && runes[i] != '\n' {
+				i++
+			}
+			span("comment", string(runes[start:i]))
+		case r == '/' && i+1 < len(runes) && runes[i+1] == '*':
+			for i += 2; i < len(runes) && !(runes[i] == '*' && i+1 < len(runes) && runes[i+1] == '/'); i++ {
+			}
+			if i < len(runes) {
+				i += 2
+			}
+			span("comment", string(runes[start:i]))
+		case r == '"' || r == '\'' || r == '`':
+			for i++; i < len(runes) && runes[i] != r && (r == '`' || runes[i] != '\n'); i++ {
+				if runes[i] == '\\' && r != '`' {
+					i++
+				}
+			}
+			if i < len(runes) {
+				i++
+			} else {
+				i = len(runes)
+			}
+			span("string", string(runes[start:i]))
+		case unicode.IsDigit(r):
+			for i < len(runes) && (unicode.IsDigit(runes[i]) || unicode.IsLetter(runes[i]) || runes[i] == '.') {
+				i++
+			}
+			span("number", string(runes[start:i]))
+		case unicode.IsLetter(r) || r == '_':
+			for i < len(runes) && (unicode.IsLetter(runes[i]) || unicode.IsDigit(runes[i]) || runes[i] == '_') {
+				i++
+			}
+			if word := string(runes[start:i]); isKeyword[word] {
+				span("keyword", word)
+			} else {
+				rendered.WriteString(word)
+			}
+		default:
+			i++
+			rendered.WriteString(html.EscapeString(string(r)))
+		}
+	}
+
+	return fmt.Sprintf(`<pre><code class="language-%s">%s</code></pre>`, language, rendered.String())
+}
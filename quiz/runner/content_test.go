@@ -0,0 +1,61 @@
+package runner
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestIoRunnerShowsTheTextFallbackOfRichContent(t *testing.T) {
+	var output bytes.Buffer
+	ioRunner := newIoRunner(strings.NewReader("10\n"), &output)
+
+	ioRunner.Ask(context.Background(), 0, Question{
+		Text:     "What does the **program** print? See [the docs](https://go.dev)",
+		Markdown: true,
+		Code:     "package main\n\nfunc main() {\n\tfmt.Println(5 + 5)\n}\n",
+		Language: "go",
+		Image:    "images/gopher.png",
+	})
+
+	expected := "Problem #0: What does the program print? See the docs (https://go.dev)\n" +
+		"    package main\n    \n    func main() {\n    \tfmt.Println(5 + 5)\n    }\n" +
+		"  [Image: images/gopher.png]\nAnswer: "
+	if output.String() != expected {
+		t.Errorf("Expected question %q, but got %q", expected, output.String())
+	}
+}
+
+func TestRenderHTMLEscapesAndRendersMarkdown(t *testing.T) {
+	media := func(path string) string { return "media/" + path }
+
+	rendered := renderHTML(Question{
+		Text:     "# Title\nIs `a<b` **true**?\n\n- [yes](javascript:void)\n- ![no](no.png)",
+		Markdown: true,
+	}, media)
+
+	expected := "<h3>Title</h3><p>Is <code>a&lt;b</code> <strong>true</strong>?</p>" +
+		`<ul><li>yes</li><li><img src="media/no.png" alt="no"></li></ul>`
+	if rendered != expected {
+		t.Errorf("Expected HTML %q, but got %q", expected, rendered)
+	}
+
+	if rendered := renderHTML(Question{Text: "<script>"}, media); rendered != "<p>&lt;script&gt;</p>" {
+		t.Errorf("Expected plain text to be escaped, but got %q", rendered)
+	}
+}
+
+func TestRenderCodeHighlightsTheSyntax(t *testing.T) {
+	rendered := renderCode(`return "a<b" // 42`, "golang")
+
+	expected := `<pre><code class="language-go"><span class="keyword">return</span> <span class="string">&#34;a&lt;b&#34;</span> ` +
+		`<span class="comment">// 42</span></code></pre>`
+	if rendered != expected {
+		t.Errorf("Expected code %q, but got %q", expected, rendered)
+	}
+
+	if rendered := renderCode("x <- 1", "haskell"); rendered != "<pre><code>x &lt;- 1</code></pre>" {
+		t.Errorf("Expected code in unknown languages to be escaped, but got %q", rendered)
+	}
+}
@@ -2,8 +2,14 @@ package runner
 
 import (
 	"bufio"
+	"context"
 	"fmt"
 	"io"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/roberveral/gophercises/quiz/report"
 )
 
 // Runner is an interface which allows to define a quiz runner. A quiz runner
@@ -12,12 +18,154 @@ import (
 //
 // There can be different implementations: using the stdin/stdout, network calls, etc.
 type Runner interface {
-	// Ask asks a question to the user and retrieves its answer.
-	Ask(number int, question string) string
+	// Ask asks a question to the user and retrieves its answer. When the given context is done
+	// the question has been dropped, because its time or the time of the quiz expired, so Ask
+	// has to return as soon as possible. The answer returned in that case is ignored.
+	Ask(ctx context.Context, number int, question Question) string
 	// ShowResults shows the results of the quiz to the user.
-	ShowResults(correctAnswers, totalAnswers int)
-	// NotifyTimeout notifies the user that the time to complete the quiz has expired.
-	NotifyTimeout(correctAnswers, totalAnswers int)
+	ShowResults(quizReport *report.Report)
+	// NotifyTimeout notifies the user that the time to complete the quiz has expired,
+	// showing the results of the quiz.
+	NotifyTimeout(quizReport *report.Report)
+	// NotifyQuestionTimeout notifies the user that the time to answer the given question has
+	// expired, so the quiz moves on to the next one.
+	NotifyQuestionTimeout(number int)
+}
+
+// PauseCommand is the answer the user gives to pause the quiz, when the Runner is a Pauser.
+const PauseCommand = "/pause"
+
+// Pauser is implemented by the Runners which let the user pause the quiz by answering
+// PauseCommand to any question.
+type Pauser interface {
+	// Pause notifies the user that the quiz is paused and waits until the user resumes it,
+	// or until the given context is done.
+	Pause(ctx context.Context)
+}
+
+// Question is the question asked to the user, with the choices the user can pick from, if any.
+type Question struct {
+	// Text of the question
+	Text string `json:"text"`
+	// Choices offered to the user. When empty the question is answered with free text.
+	Choices []string `json:"choices,omitempty"`
+	// Multiple indicates that the user can pick several choices.
+	Multiple bool `json:"multiple,omitempty"`
+	// Markdown indicates that the text of the question is written in Markdown
+	Markdown bool `json:"markdown,omitempty"`
+	// Code snippet shown along with the question, if any
+	Code string `json:"code,omitempty"`
+	// Language of the code snippet, used to highlight its syntax
+	Language string `json:"language,omitempty"`
+	// Image is the path or the URL of an image shown along with the question, if any
+	Image string `json:"image,omitempty"`
+	// Total is the number of questions in the quiz
+	Total int `json:"total,omitempty"`
+	// Remaining is the time left to complete the quiz when the question is asked, zero if the
+	// quiz has no time limit or it is unknown
+	Remaining time.Duration `json:"-"`
+	// QuestionRemaining is the time left to answer the question when it is asked, zero if the
+	// question has no time limit
+	QuestionRemaining time.Duration `json:"-"`
+}
+
+// Feedback is the outcome of the answer to a question. The expected answers and the explanation
+// are only revealed when the quiz is taken in feedback mode, so the user can learn as they go.
+type Feedback struct {
+	// Number of the question answered
+	Number int `json:"number"`
+	// Correct indicates whether the answer was correct
+	Correct bool `json:"correct"`
+	// TimedOut indicates that the question went unanswered because its time expired
+	TimedOut bool `json:"timedOut,omitempty"`
+	// Answer given by the user
+	Answer string `json:"answer,omitempty"`
+	// Expected answers to the question, only in feedback mode
+	Expected []string `json:"expected,omitempty"`
+	// Explanation of the answer, if any, only in feedback mode
+	Explanation string `json:"explanation,omitempty"`
+	// Winner is the name of the contestant who answered the question first in a match, when
+	// it wasn't the user. Empty if nobody did, or out of matches.
+	Winner string `json:"winner,omitempty"`
+}
+
+// Revealed checks whether the feedback reveals the expected answers, as in feedback mode.
+func (f Feedback) Revealed() bool {
+	return len(f.Expected) > 0
+}
+
+// incorrect checks whether the user answered wrongly, instead of running out of time or, in a
+// match, being beaten to the answer by another contestant.
+func (f Feedback) incorrect() bool {
+	return !f.Correct && !f.TimedOut && (f.Winner == "" || f.Answer != "")
+}
+
+// FeedbackRunner is implemented by the Runners which give feedback to the user after each answer.
+type FeedbackRunner interface {
+	// NotifyAnswer notifies the user of the outcome of the answer to a question. It is called
+	// after each answer and, in feedback mode, also after each question which timed out.
+	NotifyAnswer(feedback Feedback)
+}
+
+// Standing is the position of a contestant in the final standings of a match.
+type Standing struct {
+	// Rank of the contestant, starting at 1. Contestants with the same points and correct
+	// answers share the rank.
+	Rank int `json:"rank"`
+	// Name of the contestant
+	Name string `json:"name"`
+	// Correct is the number of questions the contestant answered first
+	Correct int `json:"correct"`
+	// Points scored in the match
+	Points float64 `json:"points"`
+	// Report of the answers given by the contestant
+	Report *report.Report `json:"-"`
+}
+
+// StandingsRunner is implemented by the Runners which show the final standings of a match.
+type StandingsRunner interface {
+	// ShowStandings shows the final standings of a match, ranked by points.
+	ShowStandings(standings []Standing)
+}
+
+// promptPart is a part of the prompt of a question which runners can style.
+type promptPart int
+
+const (
+	// promptHeader is the header of the problem, with its number
+	promptHeader promptPart = iota
+	// promptChoice is the number of a choice
+	promptChoice
+)
+
+// prompt builds the prompt which asks the question as text, with the given number. The parts
+// which can be styled are passed through the given style function, if any.
+func (q Question) prompt(number int, style func(part promptPart, text string) string) string {
+	if style == nil {
+		style = func(part promptPart, text string) string { return text }
+	}
+
+	var prompt strings.Builder
+	header := style(promptHeader, fmt.Sprintf("Problem #%v:", number))
+	attachments := q.attachments()
+	if len(q.Choices) == 0 && attachments == "" {
+		fmt.Fprintf(&prompt, "%s %s = ", header, q.text())
+		return prompt.String()
+	}
+
+	fmt.Fprintf(&prompt, "%s %s\n%s", header, q.text(), attachments)
+	for i, choice := range q.Choices {
+		fmt.Fprintf(&prompt, "  %s %s\n", style(promptChoice, fmt.Sprintf("%d)", i+1)), choice)
+	}
+	switch {
+	case len(q.Choices) == 0:
+		fmt.Fprint(&prompt, "Answer: ")
+	case q.Multiple:
+		fmt.Fprint(&prompt, "Choose all that apply (comma separated): ")
+	default:
+		fmt.Fprint(&prompt, "Choose one: ")
+	}
+	return prompt.String()
 }
 
 // IoRunner is a Runner which implements the interface by reading from a given io.Reader
@@ -26,28 +174,145 @@ type Runner interface {
 type IoRunner struct {
 	reader *bufio.Reader
 	writer io.Writer
+	// lines read from the reader. Reading is done in the background so an Ask can be
+	// abandoned without losing the next line typed by the user.
+	lines     chan string
+	readLines sync.Once
+	// closed is closed when the runner stops reading answers
+	closed    chan struct{}
+	closeOnce sync.Once
 }
 
 // NewIoRunner creates a new Runner which implements the interface by reading from a given io.Reader
 // and writting to a given io.Writer.
 func NewIoRunner(reader io.Reader, writer io.Writer) Runner {
-	return &IoRunner{bufio.NewReader(reader), writer}
+	return newIoRunner(reader, writer)
+}
+
+func newIoRunner(reader io.Reader, writer io.Writer) *IoRunner {
+	return &IoRunner{
+		reader: bufio.NewReader(reader),
+		writer: writer,
+		lines:  make(chan string),
+		closed: make(chan struct{}),
+	}
 }
 
 // Ask asks a question to the user and retrieves its answer.
-func (r *IoRunner) Ask(number int, question string) string {
-	fmt.Fprintf(r.writer, "Problem #%v: %s = ", number, question)
-	answer, _ := r.reader.ReadString('\n')
-	return answer
+// Choices are shown numbered, so the user can answer either with the number of the choice or with its text.
+// Rich content is shown as text: Markdown without markup, code indented and the path of the image.
+func (r *IoRunner) Ask(ctx context.Context, number int, question Question) string {
+	fmt.Fprint(r.writer, question.prompt(number, nil))
+	return r.readLine(ctx)
 }
 
 // ShowResults shows the results of the quiz to the user.
-func (r *IoRunner) ShowResults(correctAnswers, totalAnswers int) {
-	fmt.Fprintf(r.writer, "Scored %v out of %v\n", correctAnswers, totalAnswers)
+func (r *IoRunner) ShowResults(quizReport *report.Report) {
+	fmt.Fprintf(r.writer, "Scored %v out of %v\n", quizReport.Correct, quizReport.Total)
+	fmt.Fprintf(r.writer, "Points: %v out of %v (%.1f%%)\n", quizReport.Points, quizReport.MaxPoints, quizReport.Percentage())
+
+	if quizReport.PassMark > 0 {
+		result := "FAILED"
+		if quizReport.Passed() {
+			result = "PASSED"
+		}
+		fmt.Fprintf(r.writer, "%s (pass mark %v%%)\n", result, quizReport.PassMark)
+	}
+	if quizReport.Ability != 0 {
+		fmt.Fprintf(r.writer, "Estimated ability: %.0f\n", quizReport.Ability)
+	}
+	if quizReport.Seed != 0 {
+		fmt.Fprintf(r.writer, "Seed: %d\n", quizReport.Seed)
+	}
+}
+
+// NotifyAnswer shows whether the answer was correct along with the expected answers and the
+// explanation. Feedback is only shown when it reveals the expected answers, so the output of the
+// quiz doesn't change out of feedback mode, but who answered first in a match is always shown.
+func (r *IoRunner) NotifyAnswer(feedback Feedback) {
+	if feedback.Winner != "" {
+		fmt.Fprintf(r.writer, "\n%s answered first!\n", feedback.Winner)
+	}
+	if !feedback.Revealed() {
+		return
+	}
+
+	if feedback.Correct {
+		fmt.Fprintln(r.writer, "Correct!")
+	} else {
+		if feedback.incorrect() {
+			fmt.Fprintln(r.writer, "Incorrect!")
+		}
+		fmt.Fprintf(r.writer, "The answer is: %s\n", strings.Join(feedback.Expected, " / "))
+	}
+	if feedback.Explanation != "" {
+		fmt.Fprintln(r.writer, feedback.Explanation)
+	}
+}
+
+// ShowStandings shows the final standings of a match.
+func (r *IoRunner) ShowStandings(standings []Standing) {
+	fmt.Fprintln(r.writer, "Final standings:")
+	for _, standing := range standings {
+		fmt.Fprintf(r.writer, "  %d. %s: %v points (%d correct)\n", standing.Rank, standing.Name, standing.Points, standing.Correct)
+	}
+}
+
+// Pause notifies the user that the quiz is paused and waits until the user presses Enter.
+func (r *IoRunner) Pause(ctx context.Context) {
+	fmt.Fprint(r.writer, "Quiz paused, the time is stopped. Press Enter to resume... ")
+	r.readLine(ctx)
 }
 
 // NotifyTimeout notifies the user that the time to complete the quiz has expired.
-func (r *IoRunner) NotifyTimeout(correctAnswers, totalAnswers int) {
+func (r *IoRunner) NotifyTimeout(quizReport *report.Report) {
 	fmt.Fprintln(r.writer, "\nOooh! Time is past!")
-	r.ShowResults(correctAnswers, totalAnswers)
+	r.ShowResults(quizReport)
+}
+
+// NotifyQuestionTimeout notifies the user that the time to answer the given question has expired.
+func (r *IoRunner) NotifyQuestionTimeout(number int) {
+	fmt.Fprintf(r.writer, "\nTime is up for problem #%v!\n", number)
+}
+
+// Close stops reading answers, so any pending or further Ask returns an empty answer.
+// The reader isn't closed, but the runner doesn't block on it anymore once it is closed
+// by its owner.
+func (r *IoRunner) Close() {
+	r.closeOnce.Do(func() { close(r.closed) })
+}
+
+// readLine returns the next line typed by the user, or an empty line if the given context is
+// done or the runner is closed before.
+func (r *IoRunner) readLine(ctx context.Context) string {
+	r.readLines.Do(func() { go r.readLoop() })
+
+	select {
+	case line := <-r.lines:
+		return line
+	case <-ctx.Done():
+		return ""
+	case <-r.closed:
+		return ""
+	}
+}
+
+// readLoop reads the lines from the reader until it is exhausted or the runner is closed,
+// closing the lines channel so any further Ask returns an empty answer.
+func (r *IoRunner) readLoop() {
+	defer close(r.lines)
+
+	for {
+		line, err := r.reader.ReadString('\n')
+		if line != "" {
+			select {
+			case r.lines <- line:
+			case <-r.closed:
+				return
+			}
+		}
+		if err != nil {
+			return
+		}
+	}
 }
@@ -0,0 +1,39 @@
+package runner
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestIoRunnerFeedbackRevealsTheAnswer(t *testing.T) {
+	var output bytes.Buffer
+	ioRunner := newIoRunner(strings.NewReader(""), &output)
+
+	ioRunner.NotifyAnswer(Feedback{Number: 0, Correct: true})
+	if output.Len() != 0 {
+		t.Errorf("Expected no feedback out of feedback mode, but got %q", output.String())
+	}
+
+	ioRunner.NotifyAnswer(Feedback{Number: 0, Correct: true, Expected: []string{"10"}})
+	ioRunner.NotifyAnswer(Feedback{Number: 1, Answer: "3", Expected: []string{"2", "two"}, Explanation: "One plus one is two"})
+	ioRunner.NotifyAnswer(Feedback{Number: 2, TimedOut: true, Expected: []string{"4"}})
+
+	expected := "Correct!\nIncorrect!\nThe answer is: 2 / two\nOne plus one is two\nThe answer is: 4\n"
+	if output.String() != expected {
+		t.Errorf("Expected feedback %q, but got %q", expected, output.String())
+	}
+}
+
+func TestIoRunnerShowsTheMatchWinnerAndStandings(t *testing.T) {
+	var output bytes.Buffer
+	ioRunner := newIoRunner(strings.NewReader(""), &output)
+
+	ioRunner.NotifyAnswer(Feedback{Number: 0, Winner: "Blue"})
+	ioRunner.ShowStandings([]Standing{{Rank: 1, Name: "Blue", Correct: 1, Points: 1}, {Rank: 2, Name: "Red"}})
+
+	expected := "\nBlue answered first!\nFinal standings:\n  1. Blue: 1 points (1 correct)\n  2. Red: 0 points (0 correct)\n"
+	if output.String() != expected {
+		t.Errorf("Expected output %q, but got %q", expected, output.String())
+	}
+}
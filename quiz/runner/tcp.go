@@ -0,0 +1,53 @@
+package runner
+
+import (
+	"fmt"
+	"net"
+
+	"github.com/pkg/errors"
+)
+
+// TCPRunner is a Runner for a user connected through a raw TCP socket, so the quiz can be
+// taken with tools like netcat or telnet. It behaves like an IoRunner over the connection.
+type TCPRunner struct {
+	*IoRunner
+	conn net.Conn
+}
+
+// NewTCPRunner creates a new Runner which asks the questions through the given connection.
+func NewTCPRunner(conn net.Conn) *TCPRunner {
+	return &TCPRunner{newIoRunner(conn, conn), conn}
+}
+
+// RemoteAddr returns the address of the connected user.
+func (r *TCPRunner) RemoteAddr() net.Addr {
+	return r.conn.RemoteAddr()
+}
+
+// Reject tells the user that the quiz can't be taken, for the given reason.
+func (r *TCPRunner) Reject(reason string) {
+	fmt.Fprintf(r.conn, "Unable to take the quiz: %s\n", reason)
+}
+
+// Close stops reading answers and closes the connection, which releases any pending Ask.
+func (r *TCPRunner) Close() error {
+	r.IoRunner.Close()
+	return r.conn.Close()
+}
+
+// ServeTCP accepts connections from the given listener until it fails, handling each one in its
+// own goroutine with a TCPRunner for the connection. The connection is closed once handle returns,
+// so the handler doesn't need to take care of pending questions when the quiz ends.
+func ServeTCP(listener net.Listener, handle func(*TCPRunner)) error {
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			return errors.Wrap(err, "Unable to accept connection")
+		}
+
+		go func(tcpRunner *TCPRunner) {
+			defer tcpRunner.Close()
+			handle(tcpRunner)
+		}(NewTCPRunner(conn))
+	}
+}
@@ -0,0 +1,62 @@
+package runner
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"io/ioutil"
+	"net"
+	"testing"
+	"time"
+)
+
+func TestServeTCPAsksThroughTheConnection(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Expected a listener, but an error was returned: %+v", err)
+	}
+	defer listener.Close()
+
+	answers := make(chan string)
+	go ServeTCP(listener, func(tcpRunner *TCPRunner) {
+		answers <- tcpRunner.Ask(context.Background(), 0, Question{Text: "5+5"})
+	})
+
+	conn, err := net.Dial("tcp", listener.Addr().String())
+	if err != nil {
+		t.Fatalf("Expected a connection, but an error was returned: %+v", err)
+	}
+	defer conn.Close()
+
+	prompt, _ := bufio.NewReader(conn).ReadString('=')
+	if prompt != "Problem #0: 5+5 =" {
+		t.Errorf("Expected the question to be asked, but got %q", prompt)
+	}
+
+	conn.Write([]byte("10\r\n"))
+	if answer := <-answers; answer != "10\r\n" {
+		t.Errorf("Expected answer '10', but got %q", answer)
+	}
+}
+
+func TestTCPRunnerCloseReleasesPendingAsk(t *testing.T) {
+	client, conn := net.Pipe()
+	defer client.Close()
+	go io.Copy(ioutil.Discard, client)
+
+	tcpRunner := NewTCPRunner(conn)
+	answer := make(chan string)
+	go func() { answer <- tcpRunner.Ask(context.Background(), 0, Question{Text: "5+5"}) }()
+
+	time.Sleep(10 * time.Millisecond)
+	tcpRunner.Close()
+
+	select {
+	case result := <-answer:
+		if result != "" {
+			t.Errorf("Expected no answer after closing, but got %q", result)
+		}
+	case <-time.After(time.Second):
+		t.Error("Expected the pending Ask to return after closing the runner")
+	}
+}
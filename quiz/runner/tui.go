@@ -0,0 +1,206 @@
+package runner
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/roberveral/gophercises/quiz/report"
+)
+
+// ANSI escape sequences used by the TUIRunner.
+const (
+	ansiReset      = "\x1b[0m"
+	ansiBold       = "\x1b[1m"
+	ansiRed        = "\x1b[31m"
+	ansiGreen      = "\x1b[32m"
+	ansiYellow     = "\x1b[33m"
+	ansiCyan       = "\x1b[36m"
+	ansiClearLine  = "\x1b[2K"
+	ansiSaveCursor = "\x1b7"
+	ansiLoadCursor = "\x1b8"
+)
+
+// progressWidth is the number of characters of the progress bar.
+const progressWidth = 20
+
+// TUIRunner is a Runner for terminals which shows, above each question, a progress bar and a
+// live countdown of the time left, and gives colored feedback after each answer.
+type TUIRunner struct {
+	*IoRunner
+
+	// mutex serializes the writes of the countdown with the rest of the output
+	mutex sync.Mutex
+}
+
+// NewTUIRunner creates a Runner which reads the answers from the given reader and shows the
+// quiz in the given terminal. When the output is not a terminal it falls back to an IoRunner.
+func NewTUIRunner(reader io.Reader, terminal *os.File) Runner {
+	if !IsTerminal(terminal) {
+		return NewIoRunner(reader, terminal)
+	}
+	return newTUIRunner(reader, terminal)
+}
+
+func newTUIRunner(reader io.Reader, writer io.Writer) *TUIRunner {
+	return &TUIRunner{IoRunner: newIoRunner(reader, writer)}
+}
+
+// IsTerminal checks whether the given file is a terminal.
+func IsTerminal(file *os.File) bool {
+	info, err := file.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// Ask asks a question to the user and retrieves its answer, redrawing the status line with the
+// time left every second until the user answers.
+func (r *TUIRunner) Ask(ctx context.Context, number int, question Question) string {
+	start := time.Now()
+	prompt := question.prompt(number, func(part promptPart, text string) string {
+		if part == promptHeader {
+			return ansiBold + text + ansiReset
+		}
+		return ansiCyan + text + ansiReset
+	})
+	// The status line is redrawn by moving the cursor up to it
+	lines := 1 + strings.Count(prompt, "\n")
+
+	r.mutex.Lock()
+	fmt.Fprintf(r.writer, "\n%s\n%s", r.status(number, question, 0), prompt)
+	r.mutex.Unlock()
+
+	// The countdown is redrawn while the user types the answer
+	done := make(chan struct{})
+	var redrawing sync.WaitGroup
+	defer redrawing.Wait()
+	defer close(done)
+	if question.Remaining > 0 || question.QuestionRemaining > 0 {
+		redrawing.Add(1)
+		go func() {
+			defer redrawing.Done()
+			ticker := time.NewTicker(time.Second)
+			defer ticker.Stop()
+			for {
+				select {
+				case <-ticker.C:
+					r.mutex.Lock()
+					fmt.Fprintf(r.writer, "%s\x1b[%dA\r%s%s%s", ansiSaveCursor, lines, ansiClearLine,
+						r.status(number, question, time.Since(start)), ansiLoadCursor)
+					r.mutex.Unlock()
+				case <-done:
+					return
+				}
+			}
+		}()
+	}
+
+	return r.readLine(ctx)
+}
+
+// status renders the progress bar and the time left to complete the quiz and the question,
+// after the given time since the question was asked.
+func (r *TUIRunner) status(number int, question Question, elapsed time.Duration) string {
+	var status strings.Builder
+
+	if question.Total > 0 {
+		filled := progressWidth * number / question.Total
+		fmt.Fprintf(&status, "%s%s%s%s %d/%d", ansiGreen, strings.Repeat("█", filled), ansiReset,
+			strings.Repeat("░", progressWidth-filled), number, question.Total)
+	}
+	if question.Remaining > 0 {
+		fmt.Fprintf(&status, "  Time left: %s", countdown(question.Remaining-elapsed))
+	}
+	if question.QuestionRemaining > 0 {
+		fmt.Fprintf(&status, "  Question: %s", countdown(question.QuestionRemaining-elapsed))
+	}
+	return status.String()
+}
+
+// countdown formats the time left as minutes and seconds, in yellow for the last 10 seconds.
+func countdown(left time.Duration) string {
+	if left < 0 {
+		left = 0
+	}
+	seconds := int((left + time.Second - 1) / time.Second)
+	formatted := fmt.Sprintf("%d:%02d", seconds/60, seconds%60)
+	if seconds <= 10 {
+		return ansiYellow + formatted + ansiReset
+	}
+	return formatted
+}
+
+// NotifyAnswer shows whether the answer was correct, in green or red, who answered first in a
+// match and, in feedback mode, the expected answers and the explanation.
+func (r *TUIRunner) NotifyAnswer(feedback Feedback) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if feedback.Correct {
+		fmt.Fprintf(r.writer, "%s✔ Correct!%s\n", ansiGreen, ansiReset)
+	} else if feedback.incorrect() {
+		fmt.Fprintf(r.writer, "%s✘ Incorrect%s\n", ansiRed, ansiReset)
+	}
+	if feedback.Winner != "" {
+		fmt.Fprintf(r.writer, "%s%s answered first!%s\n", ansiYellow, feedback.Winner, ansiReset)
+	}
+
+	if feedback.Revealed() {
+		if !feedback.Correct {
+			fmt.Fprintf(r.writer, "The answer is: %s%s%s\n", ansiBold, strings.Join(feedback.Expected, " / "), ansiReset)
+		}
+		if feedback.Explanation != "" {
+			fmt.Fprintf(r.writer, "%s%s%s\n", ansiCyan, feedback.Explanation, ansiReset)
+		}
+	}
+}
+
+// Pause notifies the user that the quiz is paused and waits until the user presses Enter.
+func (r *TUIRunner) Pause(ctx context.Context) {
+	r.mutex.Lock()
+	fmt.Fprintf(r.writer, "%sQuiz paused, the time is stopped. Press Enter to resume...%s ", ansiYellow, ansiReset)
+	r.mutex.Unlock()
+	r.readLine(ctx)
+}
+
+// ShowResults shows the results of the quiz to the user.
+func (r *TUIRunner) ShowResults(quizReport *report.Report) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	fmt.Fprintf(r.writer, "\n%sScored %v out of %v%s\n", ansiBold, quizReport.Correct, quizReport.Total, ansiReset)
+	fmt.Fprintf(r.writer, "Points: %v out of %v (%.1f%%)\n", quizReport.Points, quizReport.MaxPoints, quizReport.Percentage())
+
+	if quizReport.PassMark > 0 {
+		if quizReport.Passed() {
+			fmt.Fprintf(r.writer, "%sPASSED%s (pass mark %v%%)\n", ansiGreen, ansiReset, quizReport.PassMark)
+		} else {
+			fmt.Fprintf(r.writer, "%sFAILED%s (pass mark %v%%)\n", ansiRed, ansiReset, quizReport.PassMark)
+		}
+	}
+	if quizReport.Ability != 0 {
+		fmt.Fprintf(r.writer, "Estimated ability: %.0f\n", quizReport.Ability)
+	}
+	if quizReport.Seed != 0 {
+		fmt.Fprintf(r.writer, "Seed: %d\n", quizReport.Seed)
+	}
+}
+
+// NotifyTimeout notifies the user that the time to complete the quiz has expired.
+func (r *TUIRunner) NotifyTimeout(quizReport *report.Report) {
+	r.mutex.Lock()
+	fmt.Fprintf(r.writer, "\n%sOooh! Time is past!%s\n", ansiYellow, ansiReset)
+	r.mutex.Unlock()
+	r.ShowResults(quizReport)
+}
+
+// NotifyQuestionTimeout notifies the user that the time to answer the given question has expired.
+func (r *TUIRunner) NotifyQuestionTimeout(number int) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	fmt.Fprintf(r.writer, "\n%sTime is up for problem #%v!%s\n", ansiYellow, number, ansiReset)
+}
@@ -0,0 +1,88 @@
+package runner
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNewTUIRunnerFallsBackWithoutTerminal(t *testing.T) {
+	reader, writer, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("Expected a pipe, but an error was returned: %+v", err)
+	}
+	defer reader.Close()
+	defer writer.Close()
+
+	if _, ok := NewTUIRunner(reader, writer).(*IoRunner); !ok {
+		t.Error("Expected an IoRunner when the output is not a terminal")
+	}
+}
+
+func TestTUIRunnerShowsProgressAndTimeLeft(t *testing.T) {
+	var output bytes.Buffer
+	tuiRunner := newTUIRunner(strings.NewReader("10\n"), &output)
+
+	answer := tuiRunner.Ask(context.Background(), 5, Question{Text: "5+5", Total: 10, Remaining: 95 * time.Second})
+
+	if answer != "10\n" {
+		t.Errorf("Expected answer '10', but got %q", answer)
+	}
+	if !strings.Contains(output.String(), strings.Repeat("█", 10)+ansiReset+strings.Repeat("░", 10)+" 5/10") {
+		t.Errorf("Expected half of the progress bar filled, but got %q", output.String())
+	}
+	if !strings.Contains(output.String(), "Time left: 1:35") {
+		t.Errorf("Expected the time left to be shown, but got %q", output.String())
+	}
+}
+
+func TestTUIRunnerStylesThePrompt(t *testing.T) {
+	var output bytes.Buffer
+	tuiRunner := newTUIRunner(strings.NewReader("1\n"), &output)
+
+	tuiRunner.Ask(context.Background(), 2, Question{Text: "Capital of France?", Choices: []string{"Paris", "Rome"}})
+
+	expected := ansiBold + "Problem #2:" + ansiReset + " Capital of France?\n  " + ansiCyan + "1)" + ansiReset + " Paris\n  " +
+		ansiCyan + "2)" + ansiReset + " Rome\nChoose one: "
+	if !strings.HasSuffix(output.String(), expected) {
+		t.Errorf("Expected the prompt %q, but got %q", expected, output.String())
+	}
+}
+
+func TestTUIRunnerRedrawsCountdown(t *testing.T) {
+	reader, writer, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("Expected a pipe, but an error was returned: %+v", err)
+	}
+	defer reader.Close()
+	defer writer.Close()
+
+	var output bytes.Buffer
+	tuiRunner := newTUIRunner(reader, &output)
+	go func() {
+		time.Sleep(1100 * time.Millisecond)
+		writer.Write([]byte("10\n"))
+	}()
+
+	tuiRunner.Ask(context.Background(), 0, Question{Text: "5+5", QuestionRemaining: 5 * time.Second})
+
+	if !strings.Contains(output.String(), ansiSaveCursor+"\x1b[1A\r"+ansiClearLine+"  Question: "+ansiYellow+"0:04") {
+		t.Errorf("Expected the countdown to be redrawn, but got %q", output.String())
+	}
+}
+
+func TestTUIRunnerGivesColoredFeedback(t *testing.T) {
+	var output bytes.Buffer
+	tuiRunner := newTUIRunner(strings.NewReader(""), &output)
+
+	tuiRunner.NotifyAnswer(Feedback{Number: 0, Correct: true})
+	tuiRunner.NotifyAnswer(Feedback{Number: 1})
+
+	expected := ansiGreen + "✔ Correct!" + ansiReset + "\n" + ansiRed + "✘ Incorrect" + ansiReset + "\n"
+	if output.String() != expected {
+		t.Errorf("Expected colored feedback %q, but got %q", expected, output.String())
+	}
+}
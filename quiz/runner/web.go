@@ -0,0 +1,272 @@
+package runner
+
+import (
+	"context"
+	_ "embed" // needed to embed the quiz page
+	"encoding/json"
+	"net/http"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/roberveral/gophercises/quiz/report"
+)
+
+//go:embed web.html
+var webPage []byte
+
+// pollTimeout is the maximum time a long-poll request waits for new events.
+const pollTimeout = 25 * time.Second
+
+// webEvent is an event sent to the browser. Events are numbered in sequence so the
+// browser can ask for the events it hasn't seen yet.
+type webEvent struct {
+	// Sequence number of the event
+	Sequence int `json:"sequence"`
+	// Type of the event: question, questionTimeout, feedback, timeout or results
+	Type string `json:"type"`
+	// Number of the question the event refers to
+	Number int `json:"number"`
+	// Question asked, only for question events
+	Question *Question `json:"question,omitempty"`
+	// HTML rendering of the question asked, with its rich content, only for question events
+	HTML string `json:"html,omitempty"`
+	// Feedback of the answer, only for feedback events
+	Feedback *Feedback `json:"feedback,omitempty"`
+	// Report of the quiz, only for timeout and results events
+	Report *report.Report `json:"report,omitempty"`
+}
+
+// webAnswer is the answer sent by the browser to a question.
+type webAnswer struct {
+	Number int    `json:"number"`
+	Answer string `json:"answer"`
+}
+
+// WebRunner is a Runner which allows to take the quiz from a browser. It is an http.Handler
+// which serves an HTML page with the quiz, sends the questions to the page with long-polling
+// and receives the answers from it:
+//
+// - GET / :- Serves the quiz page.
+// - GET /events?since={n} :- Waits for the events after the n-th one.
+// - POST /answer {"number": 0, "answer": "..."} :- Answers the given question.
+// - GET /media/{name} :- Serves the local images of the questions.
+type WebRunner struct {
+	mux *http.ServeMux
+
+	mutex  sync.Mutex
+	events []webEvent
+	// updated is closed and replaced each time an event is added
+	updated chan struct{}
+	// media are the paths of the local images served, by their name, and their names by path
+	media      map[string]string
+	mediaNames map[string]string
+
+	answers chan webAnswer
+
+	connected     chan struct{}
+	connectedOnce sync.Once
+	finished      chan struct{}
+	finishedOnce  sync.Once
+}
+
+// NewWebRunner creates a new Runner which allows to take the quiz from a browser.
+// The WebRunner has to be served with an http.Server for the user to connect to.
+func NewWebRunner() *WebRunner {
+	r := &WebRunner{
+		mux:        http.NewServeMux(),
+		updated:    make(chan struct{}),
+		media:      make(map[string]string),
+		mediaNames: make(map[string]string),
+		answers:    make(chan webAnswer, 1),
+		connected:  make(chan struct{}),
+		finished:   make(chan struct{}),
+	}
+
+	r.mux.HandleFunc("/", r.servePage)
+	r.mux.HandleFunc("/events", r.serveEvents)
+	r.mux.HandleFunc("/answer", r.receiveAnswer)
+	r.mux.HandleFunc("/media/", r.serveMedia)
+
+	return r
+}
+
+// Connected returns a channel which is closed when a browser connects to the WebRunner, so
+// the quiz can wait for the user before starting the timer.
+func (r *WebRunner) Connected() <-chan struct{} {
+	return r.connected
+}
+
+// Finished returns a channel which is closed when the results of the quiz have been
+// delivered to the browser.
+func (r *WebRunner) Finished() <-chan struct{} {
+	return r.finished
+}
+
+// ServeHTTP implements http.Handler.
+func (r *WebRunner) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	r.mux.ServeHTTP(w, req)
+}
+
+// Ask asks a question to the user and retrieves its answer. The question is rendered as HTML,
+// with its rich content.
+func (r *WebRunner) Ask(ctx context.Context, number int, question Question) string {
+	r.publish(webEvent{Type: "question", Number: number, Question: &question, HTML: renderHTML(question, r.mediaURL)})
+
+	for {
+		select {
+		case answer := <-r.answers:
+			// Answers to previous questions which arrived late are dropped
+			if answer.Number == number {
+				return answer.Answer
+			}
+		case <-ctx.Done():
+			return ""
+		}
+	}
+}
+
+// ShowResults shows the results of the quiz to the user.
+func (r *WebRunner) ShowResults(quizReport *report.Report) {
+	r.publish(webEvent{Type: "results", Report: quizReport})
+}
+
+// NotifyTimeout notifies the user that the time to complete the quiz has expired.
+func (r *WebRunner) NotifyTimeout(quizReport *report.Report) {
+	r.publish(webEvent{Type: "timeout", Report: quizReport})
+}
+
+// NotifyQuestionTimeout notifies the user that the time to answer the given question has expired.
+func (r *WebRunner) NotifyQuestionTimeout(number int) {
+	r.publish(webEvent{Type: "questionTimeout", Number: number})
+}
+
+// NotifyAnswer notifies the user of the outcome of the answer to a question. The page only shows
+// the feedback which reveals the expected answers.
+func (r *WebRunner) NotifyAnswer(feedback Feedback) {
+	r.publish(webEvent{Type: "feedback", Number: feedback.Number, Feedback: &feedback})
+}
+
+// publish adds a new event, waking up the browsers waiting for it.
+func (r *WebRunner) publish(event webEvent) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	event.Sequence = len(r.events)
+	r.events = append(r.events, event)
+	close(r.updated)
+	r.updated = make(chan struct{})
+}
+
+// mediaURL returns the URL of an image for the browser. Web URLs are kept, and local files are
+// served under /media/, so only the images of the questions can be read.
+func (r *WebRunner) mediaURL(path string) string {
+	if lower := strings.ToLower(path); strings.HasPrefix(lower, "http://") || strings.HasPrefix(lower, "https://") {
+		return path
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	name, ok := r.mediaNames[path]
+	if !ok {
+		name = strconv.Itoa(len(r.media)) + filepath.Ext(path)
+		r.media[name], r.mediaNames[path] = path, name
+	}
+	// The URL is relative to the page, so the quiz can be served under any path
+	return "media/" + name
+}
+
+// eventsSince returns the events after the given sequence number, or a channel to wait for them.
+func (r *WebRunner) eventsSince(since int) ([]webEvent, <-chan struct{}) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if since < len(r.events) {
+		return r.events[since:], nil
+	}
+	return nil, r.updated
+}
+
+func (r *WebRunner) servePage(w http.ResponseWriter, req *http.Request) {
+	if req.URL.Path != "/" {
+		http.NotFound(w, req)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Write(webPage)
+}
+
+func (r *WebRunner) serveEvents(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	since, err := strconv.Atoi(req.URL.Query().Get("since"))
+	if err != nil || since < 0 {
+		http.Error(w, "Invalid sequence number", http.StatusBadRequest)
+		return
+	}
+	r.connectedOnce.Do(func() { close(r.connected) })
+
+	timeout := time.NewTimer(pollTimeout)
+	defer timeout.Stop()
+
+	for {
+		events, updated := r.eventsSince(since)
+		if events != nil {
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(events)
+
+			if last := events[len(events)-1]; last.Type == "results" || last.Type == "timeout" {
+				r.finishedOnce.Do(func() { close(r.finished) })
+			}
+			return
+		}
+
+		select {
+		case <-updated:
+		case <-timeout.C:
+			w.WriteHeader(http.StatusNoContent)
+			return
+		case <-req.Context().Done():
+			return
+		}
+	}
+}
+
+func (r *WebRunner) serveMedia(w http.ResponseWriter, req *http.Request) {
+	r.mutex.Lock()
+	path, ok := r.media[strings.TrimPrefix(req.URL.Path, "/media/")]
+	r.mutex.Unlock()
+
+	if !ok {
+		http.NotFound(w, req)
+		return
+	}
+	http.ServeFile(w, req, path)
+}
+
+func (r *WebRunner) receiveAnswer(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var answer webAnswer
+	if err := json.NewDecoder(req.Body).Decode(&answer); err != nil {
+		http.Error(w, "Malformed answer", http.StatusBadRequest)
+		return
+	}
+
+	select {
+	case r.answers <- answer:
+		w.WriteHeader(http.StatusAccepted)
+	default:
+		http.Error(w, "An answer is already pending", http.StatusConflict)
+	}
+}
@@ -0,0 +1,124 @@
+package runner
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/roberveral/gophercises/quiz/report"
+)
+
+func pollEvents(t *testing.T, server *httptest.Server, since string) []webEvent {
+	t.Helper()
+	response, err := http.Get(server.URL + "/events?since=" + since)
+	if err != nil {
+		t.Fatalf("Expected valid response, but an error was returned: %+v", err)
+	}
+	defer response.Body.Close()
+
+	var events []webEvent
+	if err := json.NewDecoder(response.Body).Decode(&events); err != nil {
+		t.Fatalf("Expected valid events, but an error was returned: %+v", err)
+	}
+	return events
+}
+
+func TestWebRunnerAsksAndReceivesAnswers(t *testing.T) {
+	webRunner := NewWebRunner()
+	server := httptest.NewServer(webRunner)
+	defer server.Close()
+
+	answer := make(chan string)
+	go func() { answer <- webRunner.Ask(context.Background(), 0, Question{Text: "5+5"}) }()
+
+	events := pollEvents(t, server, "0")
+	if len(events) != 1 || events[0].Type != "question" || events[0].Question.Text != "5+5" {
+		t.Fatalf("Expected the question event, but got %+v", events)
+	}
+
+	response, err := http.Post(server.URL+"/answer", "application/json", strings.NewReader(`{"number": 0, "answer": "10"}`))
+	if err != nil || response.StatusCode != http.StatusAccepted {
+		t.Fatalf("Expected the answer to be accepted, but got %v %+v", response, err)
+	}
+
+	if result := <-answer; result != "10" {
+		t.Errorf("Expected answer '10', but got %q", result)
+	}
+
+	webRunner.ShowResults(&report.Report{Correct: 1, Total: 1})
+
+	events = pollEvents(t, server, "1")
+	if len(events) != 1 || events[0].Type != "results" || events[0].Report.Correct != 1 {
+		t.Fatalf("Expected the results event, but got %+v", events)
+	}
+
+	select {
+	case <-webRunner.Finished():
+	default:
+		t.Error("Expected the runner to be finished after delivering the results")
+	}
+}
+
+func TestWebRunnerRejectsInvalidSequenceNumbers(t *testing.T) {
+	server := httptest.NewServer(NewWebRunner())
+	defer server.Close()
+
+	for _, since := range []string{"-1", "first", ""} {
+		response, err := http.Get(server.URL + "/events?since=" + since)
+		if err != nil {
+			t.Fatalf("Expected valid response, but an error was returned: %+v", err)
+		}
+		response.Body.Close()
+		if response.StatusCode != http.StatusBadRequest {
+			t.Errorf("Expected status %d for since %q, but got %d", http.StatusBadRequest, since, response.StatusCode)
+		}
+	}
+}
+
+func TestWebRunnerAbandonsCancelledQuestions(t *testing.T) {
+	webRunner := NewWebRunner()
+	ctx, cancel := context.WithCancel(context.Background())
+
+	answer := make(chan string)
+	go func() { answer <- webRunner.Ask(ctx, 0, Question{Text: "5+5"}) }()
+	cancel()
+
+	if result := <-answer; result != "" {
+		t.Errorf("Expected no answer for an expired question, but got %q", result)
+	}
+}
+
+func TestWebRunnerServesTheImagesOfTheQuestions(t *testing.T) {
+	image := filepath.Join(t.TempDir(), "gopher.png")
+	if err := os.WriteFile(image, []byte("image"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	webRunner := NewWebRunner()
+	server := httptest.NewServer(webRunner)
+	defer server.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go webRunner.Ask(ctx, 0, Question{Text: "Who is this?", Image: image})
+
+	events := pollEvents(t, server, "0")
+	if expected := `<img src="media/0.png" alt="">`; len(events) != 1 || !strings.Contains(events[0].HTML, expected) {
+		t.Fatalf("Expected the question to show the image %s, but got %+v", expected, events)
+	}
+
+	response, err := http.Get(server.URL + "/media/0.png")
+	if err != nil || response.StatusCode != http.StatusOK {
+		t.Fatalf("Expected the image to be served, but got %v %+v", response, err)
+	}
+	response.Body.Close()
+
+	if response, err := http.Get(server.URL + "/media/1.png"); err != nil || response.StatusCode != http.StatusNotFound {
+		t.Errorf("Expected only the images of the questions to be served, but got %v %+v", response, err)
+	}
+}
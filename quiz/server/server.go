@@ -0,0 +1,365 @@
+package server
+
+import (
+	"context"
+	"crypto/hmac"
+	cryptorand "crypto/rand"
+	"crypto/sha256"
+	_ "embed" // needed to embed the join page
+	"encoding/hex"
+	"encoding/json"
+	"math/rand"
+	"net"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/roberveral/gophercises/quiz/model"
+	"github.com/roberveral/gophercises/quiz/report"
+	"github.com/roberveral/gophercises/quiz/runner"
+)
+
+//go:embed join.html
+var joinPage []byte
+
+// joinTimeout is the time a participant has to open the quiz after joining before
+// the session is discarded.
+const joinTimeout = 5 * time.Minute
+
+// Server is an http.Handler which serves a quiz to several participants at once. Each
+// participant gets its own session, with its own order of the problems and its own timer,
+// and the scores are ranked in a leaderboard:
+//
+// - GET / :- Serves the page to join the quiz and see the leaderboard.
+// - POST /join name={name} :- Creates a session for the participant and redirects to it.
+// - /session/{token}/ :- The quiz of the session, served by a runner.WebRunner.
+// - GET /leaderboard :- Obtains the participants ranked by points and completion time.
+//
+// Sessions are identified by tokens signed by the server for the participant and the host
+// which joined, so they can't be forged nor taken over from another host.
+type Server struct {
+	// OnJoin, if set, is called with the name of each participant before creating its session,
+	// which is rejected with the error returned, if any, like when the participant can't
+	// retake the quiz. Pending is the number of sessions of the participant which haven't been
+	// completed yet, so they can be counted as attempts.
+	OnJoin func(name string, pending int) error
+	// OnFinish, if set, is called with the name of each participant and the report of the
+	// quiz when the participant completes it
+	OnFinish func(name string, quizReport *report.Report)
+	// OnAnswer, if set, is called with the name of each participant and the entry of the
+	// report of each of its answers as soon as it is scored
+	OnAnswer func(name string, question report.Question)
+
+	quiz    *model.Quiz
+	timeout time.Duration
+	shuffle bool
+	mux     *http.ServeMux
+
+	// joining serializes the joins, so the attempts of a participant are checked one at a time
+	joining  sync.Mutex
+	mutex    sync.Mutex
+	sessions map[string]*session
+	// rng generates the seeds to shuffle the quiz of each participant
+	rng *rand.Rand
+	// secret signs the tokens of the sessions
+	secret []byte
+}
+
+// session is the quiz taken by a single participant.
+type session struct {
+	id       string
+	name     string
+	host     string
+	runner   *runner.WebRunner
+	started  time.Time
+	finished time.Time
+	report   *report.Report
+}
+
+// Standing is the position of a participant in the leaderboard.
+type Standing struct {
+	// Rank of the participant, starting at 1. Participants still taking the quiz aren't ranked.
+	Rank int `json:"rank,omitempty"`
+	// Name of the participant
+	Name string `json:"name"`
+	// Correct is the number of correct answers
+	Correct int `json:"correct"`
+	// Points scored in the quiz
+	Points float64 `json:"points"`
+	// Total is the number of questions in the quiz
+	Total int `json:"total"`
+	// Elapsed is the time in seconds the participant took to complete the quiz
+	Elapsed float64 `json:"elapsed"`
+	// Finished indicates whether the participant has completed the quiz
+	Finished bool `json:"finished"`
+}
+
+// New creates a new Server for the given quiz, which each participant has to complete in
+// the given time. When shuffle is set, each participant gets the problems in a different order,
+// shuffled with a seed derived from the seed of the quiz, if any.
+func New(quiz *model.Quiz, timeout time.Duration, shuffle bool) *Server {
+	seed := quiz.Seed
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+
+	s := &Server{
+		quiz:     quiz,
+		timeout:  timeout,
+		shuffle:  shuffle,
+		mux:      http.NewServeMux(),
+		sessions: make(map[string]*session),
+		rng:      rand.New(rand.NewSource(seed)),
+		secret:   make([]byte, 32),
+	}
+	if _, err := cryptorand.Read(s.secret); err != nil {
+		panic(errors.Wrap(err, "Unable to generate the secret to sign the sessions"))
+	}
+
+	s.mux.HandleFunc("/", s.servePage)
+	s.mux.HandleFunc("/join", s.join)
+	s.mux.HandleFunc("/session/", s.serveSession)
+	s.mux.HandleFunc("/leaderboard", s.serveLeaderboard)
+
+	return s
+}
+
+// ServeHTTP implements http.Handler.
+func (s *Server) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	s.mux.ServeHTTP(w, req)
+}
+
+// Leaderboard returns the participants which joined the quiz. Those who finished are ranked
+// by the points scored and, on a tie, by the time they took to complete the quiz,
+// followed by the ones still taking it.
+func (s *Server) Leaderboard() []Standing {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	standings := make([]Standing, 0, len(s.sessions))
+	for _, session := range s.sessions {
+		if session.started.IsZero() {
+			continue
+		}
+
+		standing := Standing{Name: session.name, Total: len(s.quiz.Problems)}
+		if session.report != nil {
+			standing.Finished = true
+			standing.Correct = session.report.Correct
+			standing.Points = session.report.Points
+			standing.Elapsed = session.finished.Sub(session.started).Seconds()
+		}
+		standings = append(standings, standing)
+	}
+
+	sort.Slice(standings, func(i, j int) bool {
+		a, b := standings[i], standings[j]
+		if a.Finished != b.Finished {
+			return a.Finished
+		}
+		if a.Points != b.Points {
+			return a.Points > b.Points
+		}
+		if a.Elapsed != b.Elapsed {
+			return a.Elapsed < b.Elapsed
+		}
+		return a.Name < b.Name
+	})
+
+	for i := range standings {
+		if standings[i].Finished {
+			standings[i].Rank = i + 1
+		}
+	}
+
+	return standings
+}
+
+// start creates a session for the given participant, joining from the given host, which runs
+// the quiz as soon as the participant opens it. Participants can't join while they are taking
+// the quiz in another session.
+func (s *Server) start(name, host string) (*session, error) {
+	id, err := newSessionID()
+	if err != nil {
+		return nil, err
+	}
+
+	participant := &session{id: id, name: name, host: host, runner: runner.NewWebRunner()}
+	s.mutex.Lock()
+	for _, other := range s.sessions {
+		if other.name == name && !other.started.IsZero() && other.report == nil {
+			s.mutex.Unlock()
+			return nil, errors.Errorf("%s is already taking the quiz", name)
+		}
+	}
+	s.sessions[id] = participant
+	s.mutex.Unlock()
+
+	quiz := s.quiz.Copy()
+	if s.shuffle {
+		// Each participant gets its own seed, reported in the results to reproduce the order
+		s.mutex.Lock()
+		seed := s.rng.Int63()
+		s.mutex.Unlock()
+		quiz = s.quiz.ShuffledCopy(seed)
+	}
+	if s.OnAnswer != nil {
+		quiz.OnAnswer = func(question report.Question) { s.OnAnswer(name, question) }
+	}
+
+	go func() {
+		select {
+		case <-participant.runner.Connected():
+		case <-time.After(joinTimeout):
+			s.mutex.Lock()
+			delete(s.sessions, id)
+			s.mutex.Unlock()
+			return
+		}
+
+		s.mutex.Lock()
+		participant.started = time.Now()
+		s.mutex.Unlock()
+
+		// Without timeout the quiz has no time limit
+		var timer *time.Timer
+		if s.timeout > 0 {
+			timer = time.NewTimer(s.timeout)
+		}
+		quizReport := quiz.Execute(context.Background(), participant.runner, timer)
+		finished := time.Now()
+
+		// The session is pending until the report is handled, so the attempt is always counted
+		if s.OnFinish != nil {
+			s.OnFinish(participant.name, quizReport)
+		}
+
+		s.mutex.Lock()
+		participant.finished = finished
+		participant.report = quizReport
+		s.mutex.Unlock()
+	}()
+
+	return participant, nil
+}
+
+func (s *Server) servePage(w http.ResponseWriter, req *http.Request) {
+	if req.URL.Path != "/" {
+		http.NotFound(w, req)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Write(joinPage)
+}
+
+// pending returns the number of sessions of the given participant which haven't been completed,
+// either because the quiz is being taken or because it hasn't been opened yet.
+func (s *Server) pending(name string) int {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	pending := 0
+	for _, session := range s.sessions {
+		if session.name == name && session.report == nil {
+			pending++
+		}
+	}
+	return pending
+}
+
+func (s *Server) join(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	name := strings.TrimSpace(req.FormValue("name"))
+	if name == "" {
+		http.Error(w, "A name is required to join the quiz", http.StatusBadRequest)
+		return
+	}
+
+	s.joining.Lock()
+	defer s.joining.Unlock()
+
+	if s.OnJoin != nil {
+		if err := s.OnJoin(name, s.pending(name)); err != nil {
+			http.Error(w, err.Error(), http.StatusForbidden)
+			return
+		}
+	}
+
+	participant, err := s.start(name, remoteHost(req))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusConflict)
+		return
+	}
+
+	http.Redirect(w, req, "/session/"+s.token(participant)+"/", http.StatusSeeOther)
+}
+
+func (s *Server) serveSession(w http.ResponseWriter, req *http.Request) {
+	path := strings.TrimPrefix(req.URL.Path, "/session/")
+	i := strings.Index(path, "/")
+	if i < 0 {
+		// The page needs the trailing slash to resolve its relative URLs
+		http.Redirect(w, req, req.URL.Path+"/", http.StatusMovedPermanently)
+		return
+	}
+	token := path[:i]
+	id := strings.SplitN(token, ".", 2)[0]
+
+	s.mutex.Lock()
+	participant, ok := s.sessions[id]
+	s.mutex.Unlock()
+	if !ok {
+		http.NotFound(w, req)
+		return
+	}
+	if participant.host != remoteHost(req) || !hmac.Equal([]byte(token), []byte(s.token(participant))) {
+		http.Error(w, "Invalid session token", http.StatusForbidden)
+		return
+	}
+
+	http.StripPrefix("/session/"+token, participant.runner).ServeHTTP(w, req)
+}
+
+func (s *Server) serveLeaderboard(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(s.Leaderboard())
+}
+
+// token returns the token of a session: its identifier signed for the participant and the host
+// which joined.
+func (s *Server) token(participant *session) string {
+	mac := hmac.New(sha256.New, s.secret)
+	mac.Write([]byte(participant.id + "\n" + participant.name + "\n" + participant.host))
+	return participant.id + "." + hex.EncodeToString(mac.Sum(nil))
+}
+
+// remoteHost returns the host the request comes from, without the port.
+func remoteHost(req *http.Request) string {
+	host, _, err := net.SplitHostPort(req.RemoteAddr)
+	if err != nil {
+		return req.RemoteAddr
+	}
+	return host
+}
+
+// newSessionID generates a random identifier for a session.
+func newSessionID() (string, error) {
+	id := make([]byte, 16)
+	if _, err := cryptorand.Read(id); err != nil {
+		return "", errors.Wrap(err, "Unable to generate session ID")
+	}
+	return hex.EncodeToString(id), nil
+}
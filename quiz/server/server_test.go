@@ -0,0 +1,94 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/roberveral/gophercises/quiz/model"
+	"github.com/roberveral/gophercises/quiz/report"
+)
+
+func TestLeaderboardRanksByScoreAndTime(t *testing.T) {
+	quiz := &model.Quiz{Problems: []model.Problem{model.NewProblem("5+5", []string{"10"}, nil), model.NewProblem("1+1", []string{"2"}, nil)}}
+	s := New(quiz, time.Minute, false)
+
+	start := time.Now()
+	s.sessions = map[string]*session{
+		"a": {name: "slow", started: start, finished: start.Add(20 * time.Second), report: &report.Report{Correct: 2, Points: 2}},
+		"b": {name: "fast", started: start, finished: start.Add(10 * time.Second), report: &report.Report{Correct: 2, Points: 2}},
+		"c": {name: "wrong", started: start, finished: start.Add(5 * time.Second), report: &report.Report{Correct: 1, Points: 1}},
+		"d": {name: "pending", started: start},
+		"e": {name: "not connected"},
+	}
+
+	expected := []Standing{
+		{Rank: 1, Name: "fast", Correct: 2, Points: 2, Total: 2, Elapsed: 10, Finished: true},
+		{Rank: 2, Name: "slow", Correct: 2, Points: 2, Total: 2, Elapsed: 20, Finished: true},
+		{Rank: 3, Name: "wrong", Correct: 1, Points: 1, Total: 2, Elapsed: 5, Finished: true},
+		{Name: "pending", Total: 2},
+	}
+
+	if leaderboard := s.Leaderboard(); !reflect.DeepEqual(leaderboard, expected) {
+		t.Errorf("Expected leaderboard %+v, but got %+v", expected, leaderboard)
+	}
+}
+
+func TestSessionsRequireTheSignedToken(t *testing.T) {
+	quiz := &model.Quiz{Problems: []model.Problem{model.NewProblem("5+5", []string{"10"}, nil)}}
+	s := New(quiz, time.Minute, false)
+	s.OnJoin = func(name string, pending int) error {
+		if name == "cheater" {
+			return errors.New("cheater can't retake the quiz")
+		}
+		return nil
+	}
+	server := httptest.NewServer(s)
+	defer server.Close()
+	client := &http.Client{CheckRedirect: func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse }}
+
+	response, err := client.PostForm(server.URL+"/join", url.Values{"name": {"alice"}})
+	if err != nil || response.StatusCode != http.StatusSeeOther {
+		t.Fatalf("Expected alice to join the quiz, but got %v %+v", response, err)
+	}
+	location := response.Header.Get("Location")
+
+	if response, err := client.Get(server.URL + location); err != nil || response.StatusCode != http.StatusOK {
+		t.Errorf("Expected the session to be served with its token, but got %v %+v", response, err)
+	}
+
+	id := strings.SplitN(strings.TrimPrefix(location, "/session/"), ".", 2)[0]
+	if response, err := client.Get(server.URL + "/session/" + id + ".forged/"); err != nil || response.StatusCode != http.StatusForbidden {
+		t.Errorf("Expected a forged token to be rejected, but got %v %+v", response, err)
+	}
+
+	if response, err := client.PostForm(server.URL+"/join", url.Values{"name": {"cheater"}}); err != nil || response.StatusCode != http.StatusForbidden {
+		t.Errorf("Expected the participant to be rejected, but got %v %+v", response, err)
+	}
+}
+
+func TestJoinCountsPendingSessionsAsAttempts(t *testing.T) {
+	quiz := &model.Quiz{Problems: []model.Problem{model.NewProblem("5+5", []string{"10"}, nil)}}
+	s := New(quiz, time.Minute, false)
+	s.OnJoin = func(name string, pending int) error {
+		if pending >= 1 {
+			return errors.Errorf("%s reached the maximum of 1 attempts", name)
+		}
+		return nil
+	}
+	server := httptest.NewServer(s)
+	defer server.Close()
+	client := &http.Client{CheckRedirect: func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse }}
+
+	if response, err := client.PostForm(server.URL+"/join", url.Values{"name": {"alice"}}); err != nil || response.StatusCode != http.StatusSeeOther {
+		t.Fatalf("Expected alice to join the quiz, but got %v %+v", response, err)
+	}
+	if response, err := client.PostForm(server.URL+"/join", url.Values{"name": {"alice"}}); err != nil || response.StatusCode != http.StatusForbidden {
+		t.Errorf("Expected alice to be rejected while the first session is pending, but got %v %+v", response, err)
+	}
+}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/roberveral/gophercises/quiz/results"
+)
+
+// runStats runs the stats subcommand with the given arguments, which shows the statistics
+// of the runs recorded in a results store.
+func runStats(args []string) error {
+	flags := flag.NewFlagSet("stats", flag.ExitOnError)
+	resultsPath := flags.String("results", "results.jsonl", "Path to the results store")
+	user := flags.String("user", "", "Only show the runs of the given user")
+	quiz := flags.String("quiz", "", "Only show the runs of the given quiz")
+	top := flags.Int("top", 10, "Number of hardest questions to show")
+	flags.Parse(args)
+
+	runs, err := results.NewStore(*resultsPath).Runs()
+	if err != nil {
+		return err
+	}
+	stats := results.Compute(results.Filter(runs, *user, *quiz))
+	if stats.Runs == 0 {
+		fmt.Println("No runs recorded yet")
+		return nil
+	}
+
+	fmt.Printf("Runs: %d\n\n", stats.Runs)
+
+	writer := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(writer, "USER\tRUNS\tACCURACY\tAVG. TIME\tLAST\tTREND")
+	for _, userStats := range stats.Users {
+		fmt.Fprintf(writer, "%s\t%d\t%.1f%%\t%s\t%.1f%%\t%+.1f pts/run\n", userStats.User, userStats.Runs, userStats.Accuracy(),
+			formatSeconds(userStats.AverageTime()), userStats.Percentages[len(userStats.Percentages)-1], userStats.Trend())
+	}
+	writer.Flush()
+
+	questions := stats.Questions
+	if len(questions) > *top {
+		questions = questions[:*top]
+	}
+	fmt.Println("\nHardest questions:")
+	fmt.Fprintln(writer, "QUESTION\tASKED\tACCURACY\tAVG. TIME")
+	for _, questionStats := range questions {
+		fmt.Fprintf(writer, "%s\t%d\t%.1f%%\t%s\n", questionStats.Question, questionStats.Asked, questionStats.Accuracy(),
+			formatSeconds(questionStats.AverageTime()))
+	}
+	return writer.Flush()
+}
+
+// formatSeconds formats a duration in seconds with a decimal, e.g. "2.5s".
+func formatSeconds(duration time.Duration) string {
+	return fmt.Sprintf("%.1fs", duration.Seconds())
+}
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/pkg/errors"
+)
+
+// runValidate runs the validate subcommand with the given arguments, which loads quiz files
+// and reports the mistakes found in them (see model.Quiz.Validate). It fails if a file can't be
+// loaded or has mistakes.
+func runValidate(args []string) error {
+	flags := flag.NewFlagSet("validate", flag.ExitOnError)
+	formatName := flags.String("format", "", "Format of the quiz files, guessed from their extensions if empty")
+	flags.Usage = func() {
+		fmt.Fprintf(flags.Output(), "Usage:\n  %s validate [-format name] file...\n\nFlags:\n", os.Args[0])
+		flags.PrintDefaults()
+	}
+	flags.Parse(args)
+
+	if flags.NArg() == 0 {
+		flags.Usage()
+		os.Exit(2)
+	}
+
+	invalid := 0
+	for _, path := range flags.Args() {
+		quiz, err := loadQuiz(path, *formatName)
+		if err != nil {
+			fmt.Printf("%s: %v\n", path, err)
+			invalid++
+			continue
+		}
+
+		issues := quiz.Validate()
+		if len(issues) == 0 {
+			fmt.Printf("%s: %d problems, OK\n", path, len(quiz.Problems))
+			continue
+		}
+
+		fmt.Printf("%s: %d problems, %d issues:\n", path, len(quiz.Problems), len(issues))
+		for _, issue := range issues {
+			fmt.Printf("  %v\n", issue)
+		}
+		invalid++
+	}
+
+	if invalid > 0 {
+		return errors.Errorf("%d of %d quiz files are invalid", invalid, flags.NArg())
+	}
+	return nil
+}